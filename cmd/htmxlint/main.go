@@ -0,0 +1,95 @@
+// Command htmxlint flags raw htmx attributes in templ and Go files, like `hx-get="/contacts"` or `g.Attr("hx-target", "#list")`.
+// It checks each value with the typed-htmx-go parsers, and suggests the typed call that replaces it.
+//
+// Usage:
+//
+//	htmxlint [-json] [-w] [path ...]
+//
+// Paths may be files or directories, which are checked recursively.
+//
+// With -w, htmxlint rewrites the attributes it can replace in place, adding the imports they need, and reports the rest.
+// Rewritten files expect a package-level hx variable, like `var hx = htmx.NewTempl()` or `var hx = htmx.NewGomponents()`.
+//
+// The exit status is 1 if any findings are reported, and 2 on errors.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/will-wow/typed-htmx-go/internal/lint"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print findings as a JSON array")
+	write := flag.Bool("w", false, "rewrite raw attributes into typed hx calls, and report the ones that can't be rewritten")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: htmxlint [-json] [-w] [path ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	files, err := lint.Files(flag.Args())
+	if err != nil {
+		fail(err)
+	}
+
+	findings := []lint.Finding{}
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fail(err)
+		}
+
+		if !*write {
+			fileFindings, err := lint.File(file, src)
+			if err != nil {
+				fail(err)
+			}
+			findings = append(findings, fileFindings...)
+			continue
+		}
+
+		out, rewritten, skipped, err := lint.Rewrite(file, src)
+		if err != nil {
+			fail(err)
+		}
+		findings = append(findings, skipped...)
+		if bytes.Equal(src, out) {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			fail(err)
+		}
+		if err := os.WriteFile(file, out, info.Mode().Perm()); err != nil {
+			fail(err)
+		}
+		fmt.Fprintf(os.Stderr, "%s: rewrote %d attributes\n", file, len(rewritten))
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			fail(err)
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "htmxlint:", err)
+	os.Exit(2)
+}
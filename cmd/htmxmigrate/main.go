@@ -0,0 +1,74 @@
+// Command htmxmigrate reports code that behaves differently after upgrading from htmx 1.x to 2.x.
+// It checks templ and Go files that use typed-htmx-go, along with raw attributes and htmx script tags,
+// and suggests the code change to make for each one, or how to keep the 1.x behavior.
+//
+// Usage:
+//
+//	htmxmigrate [-json] [path ...]
+//
+// Paths may be files or directories, which are checked recursively.
+// The files are analyzed together, so pass a whole module to find every use of an hx variable.
+//
+// The exit status is 1 if any findings are reported, and 2 on errors.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/will-wow/typed-htmx-go/internal/lint"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print findings as a JSON array")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: htmxmigrate [-json] [path ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	files, err := lint.Files(flag.Args())
+	if err != nil {
+		fail(err)
+	}
+
+	sources := make(map[string][]byte, len(files))
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fail(err)
+		}
+		sources[file] = src
+	}
+
+	findings, err := lint.Migrate(sources)
+	if err != nil {
+		fail(err)
+	}
+	if findings == nil {
+		findings = []lint.Finding{}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			fail(err)
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "htmxmigrate:", err)
+	os.Exit(2)
+}
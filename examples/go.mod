@@ -6,7 +6,6 @@ require github.com/a-h/templ v0.2.707
 
 require (
 	github.com/PuerkitoBio/goquery v1.9.1
-	github.com/angelofallars/htmx-go v0.5.0
 	github.com/lithammer/dedent v1.1.0
 	github.com/maragudk/gomponents v0.20.2
 	github.com/will-wow/typed-htmx-go v0.2.1
@@ -1,6 +1,7 @@
 package clicktoedit_test
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
@@ -8,6 +9,9 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/htmxtest"
+
 	"github.com/will-wow/typed-htmx-go/examples/web/clicktoedit"
 )
 
@@ -56,10 +60,10 @@ func TestEditPost(t *testing.T) {
 		form.Add("firstName", firstName)
 		form.Add("lastName", lastName)
 		form.Add("email", email)
-		body := strings.NewReader(form.Encode())
 
-		req := httptest.NewRequest("POST", "/edit/", body)
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req := htmxtest.NewRequest("POST", "/edit/", nil).
+			Form(form).
+			Build()
 
 		w := httptest.NewRecorder()
 
@@ -143,3 +147,44 @@ func TestEditPost(t *testing.T) {
 		}
 	})
 }
+
+func TestFlow(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/examples/templ/click-to-edit/", http.StripPrefix("/examples/templ/click-to-edit", clicktoedit.NewHandler(false)))
+
+	b := htmxtest.NewBrowser(mux, htmx.V1).SwapStatus(http.StatusUnprocessableEntity)
+	if err := b.Open("/examples/templ/click-to-edit/"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Click("button"); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{"firstName": "John", "lastName": "Smith", "email": "bad_email"} {
+		if err := b.Fill("input[name="+name+"]", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := b.Submit("form"); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := b.Text("input[name=email] + small"); text != "Invalid email address" {
+		t.Errorf("expected the email error, got %q", text)
+	}
+
+	if err := b.Fill("input[name=email]", "john@smith.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Submit("form"); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := b.Text("dl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "John") || !strings.Contains(text, "Smith") || !strings.Contains(text, "john@smith.com") {
+		t.Errorf("expected the updated contact, got %q", text)
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
-	htmx "github.com/angelofallars/htmx-go"
+	"github.com/will-wow/typed-htmx-go/htmx/hxres"
 
 	"github.com/will-wow/typed-htmx-go/examples/web/progressbar/exgom"
 	"github.com/will-wow/typed-htmx-go/examples/web/progressbar/extempl"
@@ -72,17 +72,14 @@ func (ex *example) progress(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res := htmx.NewResponse()
-
 	if progress >= 100 {
-		res = res.AddTrigger(htmx.Trigger(shared.TriggerDone))
+		_ = hxres.New().Trigger(shared.TriggerDone).Apply(w)
 	}
 
 	if ex.gom {
-		res.MustWrite(w)
 		_ = exgom.ProgressBar(progress).Render(w)
 	} else {
-		res.MustRenderTempl(r.Context(), w, extempl.ProgressBar(progress))
+		_ = extempl.ProgressBar(progress).Render(r.Context(), w)
 	}
 }
 
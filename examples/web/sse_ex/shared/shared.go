@@ -1,4 +1,6 @@
 package shared
 
-const CountdownEvent = "countdown"
-const ResetEvent = "reset"
+import "github.com/will-wow/typed-htmx-go/htmx/ext/sse"
+
+const CountdownEvent sse.Event = "countdown"
+const ResetEvent sse.Event = "reset"
@@ -1,101 +1,77 @@
 package sse_ex
 
 import (
-	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/a-h/templ"
+
+	"github.com/will-wow/typed-htmx-go/htmx/ext/sse"
+
 	"github.com/will-wow/typed-htmx-go/examples/web/sse_ex/exgom"
 	"github.com/will-wow/typed-htmx-go/examples/web/sse_ex/extempl"
 	"github.com/will-wow/typed-htmx-go/examples/web/sse_ex/shared"
 )
 
+// example renders the components of either the gomponents or templ version, as templ components.
 type example struct {
-	gom bool
+	page      func() templ.Component
+	countdown func() templ.Component
+	message   func(msg string) templ.Component
+	trigger   func() templ.Component
 }
 
 func NewHandler(gom bool) http.Handler {
 	mux := http.NewServeMux()
 
-	ex := example{gom: gom}
+	ex := example{
+		page:      extempl.Page,
+		countdown: extempl.Countdown,
+		message:   extempl.Message,
+		trigger:   extempl.Trigger,
+	}
+	if gom {
+		ex = example{
+			page:      func() templ.Component { return sse.Node(exgom.Page()) },
+			countdown: func() templ.Component { return sse.Node(exgom.Countdown()) },
+			message:   func(msg string) templ.Component { return sse.Node(exgom.Message(msg)) },
+			trigger:   func() templ.Component { return sse.Node(exgom.Trigger()) },
+		}
+	}
 
 	mux.HandleFunc("GET /{$}", ex.demo)
-	mux.HandleFunc("GET /countdown/{$}", ex.countdown)
+	mux.HandleFunc("GET /countdown/{$}", ex.countdownPage)
 	mux.HandleFunc("GET /countdown/feed/{$}", ex.feed)
 
 	return mux
 }
 
 func (ex *example) demo(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	if ex.gom {
-		_ = exgom.Page().Render(w)
-	} else {
-		_ = extempl.Page().Render(ctx, w)
-	}
+	_ = ex.page().Render(r.Context(), w)
 }
 
-func (ex *example) countdown(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	if ex.gom {
-		_ = exgom.Countdown().Render(w)
-	} else {
-		_ = extempl.Countdown().Render(ctx, w)
-	}
+func (ex *example) countdownPage(w http.ResponseWriter, r *http.Request) {
+	_ = ex.countdown().Render(r.Context(), w)
 }
 
 func (ex *example) feed(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		slog.Error("flush not supported")
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte("flush not supported"))
+	stream, err := sse.NewWriter(w, r)
+	if err != nil {
+		slog.Error("failed to start event stream", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-
 	for i := range 5 {
 		countMessage := strconv.Itoa(5 - i)
-		ex.sendEvent(w, shared.CountdownEvent, func() {
-			if ex.gom {
-				_ = exgom.Message(countMessage).Render(w)
-			} else {
-				_ = extempl.Message(countMessage).Render(ctx, w)
-			}
-		})
-		flusher.Flush()
+		_ = stream.Render(shared.CountdownEvent, ex.message(countMessage))
 		time.Sleep(time.Second)
 	}
 
-	ex.sendEvent(w, shared.CountdownEvent, func() {
-		if ex.gom {
-			_ = exgom.Message("Blastoff!").Render(w)
-		} else {
-			_ = extempl.Message("Blastoff!").Render(ctx, w)
-		}
-	})
-	flusher.Flush()
+	_ = stream.Render(shared.CountdownEvent, ex.message("Blastoff!"))
 	time.Sleep(2 * time.Second)
 
-	ex.sendEvent(w, shared.ResetEvent, func() {
-		if ex.gom {
-			_ = exgom.Trigger().Render(w)
-		} else {
-			_ = extempl.Trigger().Render(ctx, w)
-		}
-	})
-}
-
-func (ex *example) sendEvent(w http.ResponseWriter, event string, render func()) {
-	_, _ = fmt.Fprintf(w, "event: %s\n", event)
-	_, _ = fmt.Fprint(w, "data: ")
-	render()
-	_, _ = fmt.Fprint(w, "\n\n")
+	_ = stream.Render(shared.ResetEvent, ex.trigger())
 }
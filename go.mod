@@ -5,3 +5,7 @@ go 1.21
 require github.com/a-h/templ v0.2.707
 
 require github.com/maragudk/gomponents v0.20.2
+
+require golang.org/x/net v0.24.0
+
+require github.com/andybalholm/cascadia v1.3.2
@@ -1,8 +1,6 @@
 package htmx
 
 import (
-	"encoding/json"
-
 	"github.com/will-wow/typed-htmx-go/htmx/hxconfig"
 )
 
@@ -15,14 +13,22 @@ import (
 //		)
 //	/>
 //
+// Only the keys that differ from the defaults of the chosen [Version] are rendered.
+// Invalid config values and keys that don't exist in the chosen version are left out, and reported to [OnError].
+// If the config can't be marshaled at all, nothing is rendered.
+//
+// To render the whole meta element, use [hxconfig.Builder.Templ] or [hxconfig.Builder.Gomponents].
+//
 // [HTMX Docs]
 //
 // [HTMX Docs]: https://htmx.org/reference/#config
 func (hx *HX[T]) Config(config *hxconfig.Builder) T {
-	c := config.Build()
-	bytes, err := json.Marshal(c)
+	content, err := config.JSON(hx.opts.version)
+	if content == "" {
+		return hx.Reject(err)
+	}
 	if err != nil {
-		return hx.attr("content", "{}")
+		hx.report(err)
 	}
-	return hx.attr("content", string(bytes))
+	return hx.attr("content", content)
 }
@@ -8,7 +8,7 @@ import (
 	"github.com/will-wow/typed-htmx-go/htmx/swap"
 )
 
-// ExampleHX_Config shows the default values, which can be omitted in normal use.
+// ExampleHX_Config shows that keys set to their default values are left out.
 func ExampleHX_Config() {
 	config := hx.Config(hxconfig.New().
 		HistoryEnabled(true).
@@ -28,8 +28,8 @@ func ExampleHX_Config() {
 		InlineScriptNonce("nonce").
 		AttributesToSettle([]string{"foo"}).
 		UseTemplateFragments(false).
-		WSReconnectDelay("full-jitter").
-		WSBinaryType("blob").
+		WSReconnectDelay(hxconfig.ReconnectFullJitter).
+		WSBinaryType(hxconfig.BinaryTypeBlob).
 		DisableSelector("[hx-disable], [data-hx-disable]").
 		WithCredentials(false).
 		Timeout(time.Second). // Default is 0, this is an example
@@ -41,10 +41,10 @@ func ExampleHX_Config() {
 		SelfRequestsOnly(false).
 		IgnoreTitle(false).
 		ScrollIntoViewOnBoost(true).
-		TriggerSpecsCache("cacheObject"), // Default is nil, this is an example
+		TriggerSpecsCache(true), // Default is false, this is an example
 	)
 
 	fmt.Println(config)
 
-	// output: content='{"addedClass":"htmx-added","allowEval":true,"allowScriptTags":true,"attributesToSettle":["foo"],"defaultFocusScroll":false,"defaultSettleDelay":20,"defaultSwapDelay":0,"defaultSwapStyle":"innerHTML","disableSelector":"[hx-disable], [data-hx-disable]","getCacheBusterParam":false,"globalViewTransitions":false,"historyCacheSize":10,"historyEnabled":true,"ignoreTitle":false,"includeIndicatorStyles":true,"indicatorClass":"htmx-indicator","inlineScriptNonce":"nonce","methodsThatUseUrlParams":["get"],"refreshOnHistoryMiss":false,"requestClass":"htmx-request","scrollBehavior":"smooth","scrollIntoViewOnBoost":true,"selfRequestsOnly":false,"settlingClass":"htmx-settling","swappingClass":"htmx-swapping","timeout":1000,"triggerSpecsCache":"cacheObject","useTemplateFragments":false,"withCredentials":false,"wsBinaryType":"blob","wsReconnectDelay":"full-jitter"}'
+	// output: content='{"attributesToSettle":["foo"],"inlineScriptNonce":"nonce","timeout":1000,"triggerSpecsCache":{}}'
 }
@@ -0,0 +1,77 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+)
+
+// An ErrorCollector gathers the errors from attributes that can't be rendered during a single render, from an HX returned by [HX.WithCollector].
+// Wrap a component with [ErrorCollector.Templ] or [ErrorCollector.Gomponents] to return the errors from its render,
+// or call [ErrorCollector.Err] after executing an html/template.
+//
+// Create a new collector for each render, like in each request handler, so errors are returned from the render that caused them.
+// The zero value is ready to use, and it's safe to use from multiple goroutines.
+type ErrorCollector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// WithCollector returns a copy of the HX that adds attributes that can't be rendered to a collector, instead of the [OnError] policy.
+// Pass the copy to the components of a single render:
+//
+//	var errs htmx.ErrorCollector
+//	hx := hx.WithCollector(&errs)
+//	err := errs.Templ(page(hx)).Render(ctx, w)
+func (hx *HX[T]) WithCollector(c *ErrorCollector) HX[T] {
+	opts := hx.opts
+	opts.onError = c.add
+	return HX[T]{attr: hx.attr, opts: opts}
+}
+
+// add records an error.
+func (c *ErrorCollector) add(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errs = append(c.errs, err)
+}
+
+// Err returns the errors collected since the last call to Err, joined together, and clears them.
+// It returns nil if there are none.
+func (c *ErrorCollector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := errors.Join(c.errs...)
+	c.errs = nil
+	return err
+}
+
+// Templ wraps a templ component, and returns the errors collected while it renders.
+// The component's output is still written.
+//
+//	err := errs.Templ(page()).Render(ctx, w)
+func (c *ErrorCollector) Templ(component templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if err := component.Render(ctx, w); err != nil {
+			return errors.Join(err, c.Err())
+		}
+		return c.Err()
+	})
+}
+
+// Gomponents wraps a gomponents node, and returns the errors collected while it was built and rendered.
+// The node's output is still written.
+//
+//	err := errs.Gomponents(page()).Render(w)
+func (c *ErrorCollector) Gomponents(node g.Node) g.Node {
+	return g.NodeFunc(func(w io.Writer) error {
+		if err := node.Render(w); err != nil {
+			return errors.Join(err, c.Err())
+		}
+		return c.Err()
+	})
+}
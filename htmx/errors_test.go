@@ -0,0 +1,112 @@
+package htmx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+	"github.com/maragudk/gomponents/html"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+)
+
+func ExampleErrorCollector_Templ() {
+	var errs htmx.ErrorCollector
+	base := htmx.NewTempl()
+	hx := base.WithCollector(&errs)
+
+	// This is how generated templ code renders <button { hx.Target("")... }>Save</button>.
+	component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, err := io.WriteString(w, "<button"); err != nil {
+			return err
+		}
+		if err := templ.RenderAttributes(ctx, w, hx.Target("")); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, ">Save</button>\n")
+		return err
+	})
+
+	err := errs.Templ(component).Render(context.Background(), os.Stdout)
+	fmt.Println(err)
+	// Output:
+	// <button>Save</button>
+	// htmx: invalid attribute value: hx-target: empty selector
+}
+
+func ExampleErrorCollector_Gomponents() {
+	var errs htmx.ErrorCollector
+	base := htmx.NewGomponents()
+	hx := base.WithCollector(&errs)
+
+	err := errs.Gomponents(html.Button(hx.Post("/save"), hx.Indicator(""), g.Text("Save"))).Render(os.Stdout)
+	fmt.Println()
+	fmt.Println(err)
+	// Output:
+	// <button hx-post="/save">Save</button>
+	// htmx: invalid attribute value: hx-indicator: empty selector
+}
+
+func TestErrorCollector(t *testing.T) {
+	var errs htmx.ErrorCollector
+	hx := hx.WithCollector(&errs)
+
+	if err := errs.Err(); err != nil {
+		t.Errorf("got %v before rendering, want nil", err)
+	}
+
+	hx.Target("")
+	hx.Include("")
+	err := errs.Err()
+	if !errors.Is(err, htmx.ErrInvalid) || strings.Count(err.Error(), "\n") != 1 {
+		t.Errorf("got %v, want both errors", err)
+	}
+	if err := errs.Err(); err != nil {
+		t.Errorf("got %v after Err, want the errors cleared", err)
+	}
+}
+
+func TestHX_WithCollector(t *testing.T) {
+	var first, second htmx.ErrorCollector
+	hx1 := hx.WithCollector(&first)
+	hx2 := hx.WithCollector(&second)
+
+	hx1.Target("")
+	if got := hx2.Target("#ok"); got != "hx-target='#ok'" {
+		t.Errorf("got %q from the second copy", got)
+	}
+	if err := second.Err(); err != nil {
+		t.Errorf("got %v in the second collector, want each render's errors kept apart", err)
+	}
+	if err := first.Err(); !errors.Is(err, htmx.ErrInvalid) {
+		t.Errorf("got %v in the first collector, want ErrInvalid", err)
+	}
+}
+
+func TestHX_WithCollector_concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(invalid bool) {
+			defer wg.Done()
+			var errs htmx.ErrorCollector
+			hx := hx.WithCollector(&errs)
+			if invalid {
+				hx.Include("")
+			} else {
+				hx.Include("#search")
+			}
+			if err := errs.Err(); (err != nil) != invalid {
+				t.Errorf("got %v, want an error: %t", err, invalid)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+}
@@ -9,8 +9,14 @@ import "github.com/will-wow/typed-htmx-go/htmx"
 //
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/alpine-morph.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-alpine-morph@2/alpine-morph.js"></script>
+//
 // Extension: [alpine-morph]
 //
 // [alpine-morph]: https://htmx.org/extensions/alpine-morph/
@@ -2,18 +2,27 @@
 package classtools
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/internal/interval"
 )
 
 // Extension allows you to specify CSS classes that will be swapped onto or off of the elements by using a classes or data-classes attribute. This functionality allows you to apply CSS Transitions to your HTML without resorting to javascript.
 //
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/class-tools.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-class-tools@2/class-tools.js"></script>
+//
 // Extension: [class-tools]
 //
 // [class-tools]: https://htmx.org/extensions/class-tools/
@@ -78,25 +87,51 @@ func Classes[T any](hx htmx.HX[T], operations ...classOperation) T {
 //
 // [class-tools]: https://htmx.org/extensions/class-tools/
 func ClassesParallel[T any](hx htmx.HX[T], runs []Run) T {
-	classes := strings.Builder{}
-
+	values := make([]string, len(runs))
 	for i, run := range runs {
-		for j, op := range run {
-			classes.WriteString(string(op.operation))
-			classes.WriteRune(' ')
-			classes.WriteString(op.class)
-			classes.WriteRune(':')
-			classes.WriteString(op.delay.String())
-			if j < len(run)-1 {
-				classes.WriteString(", ")
-			}
+		if err := run.Validate(); err != nil {
+			return hx.Reject(err)
+		}
+		values[i] = run.String()
+	}
+
+	return hx.Attr("classes", strings.Join(values, " & "))
+}
+
+// Validate returns an error wrapping [ErrInvalid] if the run is empty, or an operation has an empty class name, a negative delay, or toggles with a delay of zero.
+func (r Run) Validate() error {
+	if len(r) == 0 {
+		return fmt.Errorf("%w: empty run", ErrInvalid)
+	}
+	for _, op := range r {
+		switch {
+		case strings.TrimSpace(op.class) == "":
+			return fmt.Errorf("%w: %s: missing class name", ErrInvalid, op.operation)
+		case op.delay < 0:
+			return fmt.Errorf("%w: %s %s: negative delay %s", ErrInvalid, op.operation, op.class, op.delay)
+		case op.operation == operationToggle && op.delay == 0:
+			return fmt.Errorf("%w: toggle %s: the delay must be positive", ErrInvalid, op.class)
 		}
-		if i < len(runs)-1 {
-			classes.WriteString(" & ")
+	}
+	return nil
+}
+
+// String returns the classes string for a single run of class operations. Parallel runs are joined with " & ".
+func (r Run) String() string {
+	classes := strings.Builder{}
+
+	for i, op := range r {
+		classes.WriteString(string(op.operation))
+		classes.WriteRune(' ')
+		classes.WriteString(op.class)
+		classes.WriteRune(':')
+		classes.WriteString(op.delay.String())
+		if i < len(r)-1 {
+			classes.WriteString(", ")
 		}
 	}
 
-	return hx.Attr("classes", classes.String())
+	return classes.String()
 }
 
 // Add will add a class to the element after the specified delay.
@@ -111,6 +146,7 @@ func Remove(className string, delay time.Duration) classOperation {
 }
 
 // Toggle will toggle a class on the element on and off, every time the delay elapses.
+// The delay must be positive.
 func Toggle(className string, delay time.Duration) classOperation {
 	return makeOperation(operationToggle, className, delay)
 }
@@ -122,3 +158,48 @@ func makeOperation(op operation, className string, delay time.Duration) classOpe
 		delay:     delay,
 	}
 }
+
+// ErrInvalid is returned when parsing an invalid classes value, and reported when rendering invalid operations.
+var ErrInvalid = errors.New("classtools: invalid classes value")
+
+// defaultDelay is the delay class-tools uses when an operation doesn't specify one.
+const defaultDelay = 100 * time.Millisecond
+
+// Parse parses a classes attribute value, like `add foo:500ms, remove bar & toggle baz:1s`, into parallel runs of class operations for [ClassesParallel].
+// Operations without a delay use the class-tools default of 100ms.
+func Parse(s string) ([]Run, error) {
+	var runs []Run
+
+	for _, runValue := range strings.Split(s, "&") {
+		var run Run
+		for _, opValue := range strings.Split(runValue, ",") {
+			fields := strings.Fields(opValue)
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%w: %q: expected an operation and a class in %q", ErrInvalid, s, strings.TrimSpace(opValue))
+			}
+
+			op := operation(fields[0])
+			if op != operationAdd && op != operationRemove && op != operationToggle {
+				return nil, fmt.Errorf("%w: %q: unknown operation %q", ErrInvalid, s, op)
+			}
+
+			className, delayValue, found := strings.Cut(fields[1], ":")
+			delay := defaultDelay
+			if found {
+				parsed, err := interval.Parse(delayValue)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %q: %s", ErrInvalid, s, err)
+				}
+				delay = parsed
+			}
+			if className == "" {
+				return nil, fmt.Errorf("%w: %q: missing class name", ErrInvalid, s)
+			}
+
+			run = append(run, makeOperation(op, className, delay))
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
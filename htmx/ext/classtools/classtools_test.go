@@ -1,7 +1,9 @@
 package classtools_test
 
 import (
+	"errors"
 	"fmt"
+	"testing"
 	"time"
 
 	"github.com/will-wow/typed-htmx-go/htmx"
@@ -37,5 +39,53 @@ func ExampleClassesParallel() {
 		},
 	})
 	fmt.Println(attr)
-	// Output: classes='add foo:500ms, remove bar:0s & toggle baz:1s'
+	// Output: classes='add foo:500ms, remove bar:0s &amp; toggle baz:1s'
+}
+
+func ExampleParse() {
+	runs, _ := classtools.Parse("add foo, remove bar:1s & toggle baz:500")
+	fmt.Println(classtools.ClassesParallel(hx, runs))
+	// Output: classes='add foo:100ms, remove bar:1s &amp; toggle baz:500ms'
+}
+
+func TestClassesParallel_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		runs []classtools.Run
+		want string
+	}{
+		{
+			name: "empty run",
+			runs: []classtools.Run{{}},
+			want: "classtools: invalid classes value: empty run",
+		},
+		{
+			name: "missing class",
+			runs: []classtools.Run{{classtools.Add("", time.Second)}},
+			want: "classtools: invalid classes value: add: missing class name",
+		},
+		{
+			name: "negative delay",
+			runs: []classtools.Run{{classtools.Remove("foo", -time.Second)}},
+			want: "classtools: invalid classes value: remove foo: negative delay -1s",
+		},
+		{
+			name: "toggle without delay",
+			runs: []classtools.Run{{classtools.Add("foo", 0)}, {classtools.Toggle("bar", 0)}},
+			want: "classtools: invalid classes value: toggle bar: the delay must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr error
+			hx := htmx.NewStringAttrs(htmx.OnError(func(err error) { gotErr = err }))
+
+			if got := classtools.ClassesParallel(hx, tt.runs); got != "" {
+				t.Errorf("got %q, want nothing rendered", got)
+			}
+			if !errors.Is(gotErr, classtools.ErrInvalid) || gotErr.Error() != tt.want {
+				t.Errorf("got error %v, want %q", gotErr, tt.want)
+			}
+		})
+	}
 }
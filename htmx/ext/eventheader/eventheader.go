@@ -7,8 +7,14 @@ import "github.com/will-wow/typed-htmx-go/htmx"
 //
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/event-header.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-event-header@2/event-header.js"></script>
+//
 // # Usage
 //
 //	<button { hx.Ext(eventheader.Extension)... } >
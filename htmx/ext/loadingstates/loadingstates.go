@@ -12,8 +12,14 @@ import (
 //
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/loading-states.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-loading-states@2/loading-states.js"></script>
+//
 // # Usage
 // Add the hx-ext="loading-states" attribute to the body tag or to any parent element containing your htmx attributes.
 // Add the following class to your stylesheet to make sure elements are hidden by default:
@@ -10,8 +10,14 @@ import (
 //
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/preload.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-preload@2/preload.js"></script>
+//
 // Extension: [preload]
 //
 // [preload]: https://htmx.org/extensions/preload/
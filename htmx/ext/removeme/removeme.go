@@ -11,8 +11,14 @@ import (
 //
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/remove-me.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-remove-me@2/remove-me.js"></script>
+//
 // Extension: [remove-me]
 //
 // [remove-me]: https://htmx.org/extensions/remove-me/
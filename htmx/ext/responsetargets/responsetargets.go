@@ -2,6 +2,7 @@
 package responsetargets
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,10 +12,18 @@ import (
 
 // Extension allows you to specify different target elements to be swapped when different HTTP response codes are received.
 //
+// With htmx 2, hxconfig.Builder.ResponseHandling can also swap error responses into a target, using the same codes, without an extension.
+//
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/response-targets.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-response-targets@2/response-targets.js"></script>
+//
 // Extension: [response-targets]
 //
 // [response-targets]: https://htmx.org/extensions/response-targets/
@@ -22,6 +31,8 @@ const Extension htmx.Extension = "response-targets"
 
 // A Code is a complete or partial HTTP response code.
 type Code interface {
+	// String returns the code as used in the attribute name, like "404" or "40*".
+	String() string
 	code() string
 }
 
@@ -34,6 +45,10 @@ func (s Status) code() string {
 	return strconv.Itoa(int(s))
 }
 
+func (s Status) String() string {
+	return s.code()
+}
+
 // an errorCode is the string "error", used to cover all 4xx and 5xx HTTP response codes.
 type errorCode string
 
@@ -46,6 +61,10 @@ func (e errorCode) code() string {
 	return string(e)
 }
 
+func (e errorCode) String() string {
+	return e.code()
+}
+
 // A wildcard is a partial HTTP response code with a wildcard component.
 type wildcard []int
 
@@ -66,6 +85,10 @@ func (w wildcard) code() string {
 	return builder.String()
 }
 
+func (w wildcard) String() string {
+	return w.code()
+}
+
 type wildcardX []int
 
 // WildcardX creates a wildcard code with the given digits, and uses an 'x' instead of a '*' in the generated attribute.
@@ -83,12 +106,59 @@ func (w wildcardX) code() string {
 	return builder.String()
 }
 
+func (w wildcardX) String() string {
+	return w.code()
+}
+
 // Target specifies a target element to be swapped when specific HTTP response codes are received.
 //
 // Extension: [response-targets]
 //
 // [response-targets]: https://htmx.org/extensions/response-targets/
 func Target[T any](hx htmx.HX[T], code Code, extendedSelector htmx.TargetSelector) T {
-	attr := fmt.Sprintf("hx-target-%s", code.code())
+	attr := fmt.Sprintf("%s%s", attributePrefix, code.code())
 	return hx.Attr(htmx.Attribute(attr), string(extendedSelector))
 }
+
+// attributePrefix is the prefix of every response target attribute.
+const attributePrefix = "hx-target-"
+
+// ErrInvalid is returned when parsing an invalid response code.
+var ErrInvalid = errors.New("responsetargets: invalid response code")
+
+// ParseCode parses a response code, like "404", "error", "40*" or "4x", into a [Code].
+// The code may include the hx-target- attribute prefix, so attribute names can be parsed directly.
+func ParseCode(s string) (Code, error) {
+	value := strings.TrimPrefix(s, attributePrefix)
+	if value == string(Error) {
+		return Error, nil
+	}
+
+	digits := value
+	wildcardChar := byte(0)
+	if value != "" && (value[len(value)-1] == '*' || value[len(value)-1] == 'x') {
+		digits = value[:len(value)-1]
+		wildcardChar = value[len(value)-1]
+	}
+	if (wildcardChar == 0 && len(digits) != 3) || (wildcardChar != 0 && len(digits) > 2) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	parsed := make([]int, len(digits))
+	for i, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+		parsed[i] = int(c - '0')
+	}
+
+	switch wildcardChar {
+	case '*':
+		return Wildcard(parsed...), nil
+	case 'x':
+		return WildcardX(parsed...), nil
+	default:
+		status, _ := strconv.Atoi(digits)
+		return Status(status), nil
+	}
+}
@@ -33,3 +33,16 @@ func ExampleTarget_wildcardX() {
 	fmt.Println(attr)
 	// Output: hx-target-40x='next div'
 }
+
+func ExampleParseCode() {
+	for _, value := range []string{"404", "error", "hx-target-5*", "40x", "4"} {
+		code, err := responsetargets.ParseCode(value)
+		fmt.Println(code, err)
+	}
+	// Output:
+	// 404 <nil>
+	// error <nil>
+	// 5* <nil>
+	// 40x <nil>
+	// <nil> responsetargets: invalid response code: "4"
+}
@@ -7,8 +7,14 @@ import "github.com/will-wow/typed-htmx-go/htmx"
 //
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/restored.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-restored@2/restored.js"></script>
+//
 // # Usage
 //
 // A page utilizing hx-boost that will reload the h1 each time the back button is pressed:
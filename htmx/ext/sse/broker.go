@@ -0,0 +1,261 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// ErrSlowConsumer is returned by [Broker.Serve] when a client falls too far behind, and is disconnected so it doesn't hold up other clients.
+// The EventSource will reconnect, and catch up from the replay log using the Last-Event-ID header.
+var ErrSlowConsumer = errors.New("sse: client dropped for falling behind")
+
+// ErrBrokerClosed is returned by [Broker.Publish] after the broker is closed.
+var ErrBrokerClosed = errors.New("sse: broker closed")
+
+// A Broker fans out messages published to a topic, like a user or document id, to every client subscribed to it.
+//
+// Each message gets an increasing id, and recent messages are kept in a replay log, so a reconnecting EventSource that sends a Last-Event-ID header catches up on what it missed.
+//
+//	broker := sse.NewBroker()
+//
+//	mux.HandleFunc("GET /docs/{id}/events", func(w http.ResponseWriter, r *http.Request) {
+//		_ = broker.Serve(w, r, "doc:"+r.PathValue("id"))
+//	})
+//
+//	_ = broker.Publish(ctx, "doc:42", "title", titleComponent(doc))
+type Broker struct {
+	mu         sync.Mutex
+	lastID     uint64
+	log        []entry
+	replaySize int
+	bufferSize int
+	keepAlive  time.Duration
+	topics     map[string]map[*subscriber]struct{}
+	closed     bool
+}
+
+// entry is a published message in the replay log.
+type entry struct {
+	id      uint64
+	topic   string
+	payload Payload
+}
+
+// subscriber is a single connected client.
+type subscriber struct {
+	topics []string
+	ch     chan Payload
+	err    error
+}
+
+// NewBroker starts a builder chain for a broker.
+// By default, it keeps the last 100 messages for replay, buffers up to 16 messages per client, and sends a keep-alive comment every 30 seconds.
+func NewBroker() *Broker {
+	return &Broker{
+		mu:         sync.Mutex{},
+		lastID:     0,
+		log:        nil,
+		replaySize: 100,
+		bufferSize: 16,
+		keepAlive:  30 * time.Second,
+		topics:     map[string]map[*subscriber]struct{}{},
+		closed:     false,
+	}
+}
+
+// ReplaySize sets how many recent messages, across all topics, are kept to replay to reconnecting clients.
+func (b *Broker) ReplaySize(n int) *Broker {
+	b.replaySize = n
+	return b
+}
+
+// BufferSize sets how many messages can be queued for a client before it is dropped as a slow consumer.
+// The minimum is 1; smaller values are treated as 1, since a client without a buffer would be dropped on every message.
+func (b *Broker) BufferSize(n int) *Broker {
+	b.bufferSize = max(n, 1)
+	return b
+}
+
+// KeepAlive sets how often a comment is sent to idle clients, to keep proxies from closing the connection. Zero disables keep-alives.
+func (b *Broker) KeepAlive(interval time.Duration) *Broker {
+	b.keepAlive = interval
+	return b
+}
+
+// Publish renders a templ component, or a g.Node wrapped with [Node], once, and sends it to every client subscribed to the topic, as a message with the given event name.
+// Use the same event name as the [Swap] attribute that should receive it.
+func (b *Broker) Publish(ctx context.Context, topic string, event Event, component templ.Component) error {
+	data, err := RenderString(ctx, component)
+	if err != nil {
+		return err
+	}
+	return b.PublishPayload(topic, Payload{ID: "", Event: event, Retry: 0, Data: data})
+}
+
+// PublishPayload sends a message to every client subscribed to the topic.
+// The payload's id is replaced with the broker's next message id.
+func (b *Broker) PublishPayload(topic string, p Payload) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBrokerClosed
+	}
+
+	b.lastID++
+	p.ID = strconv.FormatUint(b.lastID, 10)
+
+	if b.replaySize > 0 {
+		b.log = append(b.log, entry{id: b.lastID, topic: topic, payload: p})
+		if len(b.log) > b.replaySize {
+			b.log = b.log[len(b.log)-b.replaySize:]
+		}
+	}
+
+	for sub := range b.topics[topic] {
+		select {
+		case sub.ch <- p:
+		default:
+			b.drop(sub, ErrSlowConsumer)
+		}
+	}
+	return nil
+}
+
+// Serve streams messages published to any of the topics to the client, until the request is cancelled, the client falls behind, or the broker is closed.
+//
+// If the request has a Last-Event-ID header, messages for the topics that were published after it, and are still in the replay log, are sent first.
+// Serve returns nil when the client disconnects or the broker is closed, and [ErrSlowConsumer] if the client was dropped.
+func (b *Broker) Serve(w http.ResponseWriter, r *http.Request, topics ...string) error {
+	stream, err := NewWriter(w, r)
+	if err != nil {
+		return err
+	}
+
+	sub, replay := b.subscribe(topics, r.Header.Get("Last-Event-ID"))
+	defer b.unsubscribe(sub)
+
+	for _, p := range replay {
+		if err := stream.Send(p); err != nil {
+			return err
+		}
+	}
+
+	var tick <-chan time.Time
+	if b.keepAlive > 0 {
+		ticker := time.NewTicker(b.keepAlive)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case p, ok := <-sub.ch:
+			if !ok {
+				b.mu.Lock()
+				err := sub.err
+				b.mu.Unlock()
+				return err
+			}
+			if err := stream.Send(p); err != nil {
+				return err
+			}
+		case <-tick:
+			if err := stream.Comment("keep-alive"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Close disconnects every client, and stops accepting new messages.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subs := range b.topics {
+		for sub := range subs {
+			b.drop(sub, nil)
+		}
+	}
+}
+
+// subscribe registers a client for the topics, and returns the messages it missed since lastEventID.
+// Both happen under the same lock, so no message is missed or sent twice between the replay and the live stream.
+func (b *Broker) subscribe(topics []string, lastEventID string) (*subscriber, []Payload) {
+	sub := &subscriber{
+		topics: topics,
+		ch:     make(chan Payload, b.bufferSize),
+		err:    nil,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(sub.ch)
+		return sub, nil
+	}
+
+	var replay []Payload
+	if last, err := strconv.ParseUint(lastEventID, 10, 64); err == nil {
+		for _, e := range b.log {
+			if e.id > last && slices.Contains(topics, e.topic) {
+				replay = append(replay, e.payload)
+			}
+		}
+	}
+
+	for _, topic := range topics {
+		if b.topics[topic] == nil {
+			b.topics[topic] = map[*subscriber]struct{}{}
+		}
+		b.topics[topic][sub] = struct{}{}
+	}
+	return sub, replay
+}
+
+// unsubscribe removes a client that has disconnected.
+func (b *Broker) unsubscribe(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(sub)
+}
+
+// drop removes a client and closes its channel, so its Serve call returns err.
+// Must be called with the lock held.
+func (b *Broker) drop(sub *subscriber, err error) {
+	if b.remove(sub) {
+		sub.err = err
+		close(sub.ch)
+	}
+}
+
+// remove removes a client from all of its topics, and reports whether it was still subscribed.
+// Must be called with the lock held.
+func (b *Broker) remove(sub *subscriber) bool {
+	removed := false
+	for _, topic := range sub.topics {
+		subs := b.topics[topic]
+		if _, ok := subs[sub]; !ok {
+			continue
+		}
+		removed = true
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(b.topics, topic)
+		}
+	}
+	return removed
+}
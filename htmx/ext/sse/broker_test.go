@@ -0,0 +1,163 @@
+package sse_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+	"github.com/maragudk/gomponents/html"
+
+	"github.com/will-wow/typed-htmx-go/htmx/ext/sse"
+)
+
+func ExampleBroker() {
+	broker := sse.NewBroker().KeepAlive(0)
+
+	ctx := context.Background()
+	_ = broker.Publish(ctx, "doc:1", "title", sse.Node(html.H1(g.Text("Draft"))))
+	_ = broker.Publish(ctx, "doc:2", "title", sse.Node(html.H1(g.Text("Other"))))
+	_ = broker.Publish(ctx, "doc:1", "title", sse.Node(html.H1(g.Text("Final"))))
+
+	// A reconnecting EventSource that saw the first message.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	r := httptest.NewRequest(http.MethodGet, "/docs/1/events", nil).WithContext(ctx)
+	r.Header.Set("Last-Event-ID", "1")
+	w := httptest.NewRecorder()
+
+	_ = broker.Serve(w, r, "doc:1")
+
+	fmt.Print(w.Body.String())
+	// Output:
+	// id: 3
+	// event: title
+	// data: <h1>Final</h1>
+}
+
+// blockingWriter blocks the first write until released, to simulate a slow client.
+type blockingWriter struct {
+	*httptest.ResponseRecorder
+	started chan struct{}
+	release chan struct{}
+	once    bool
+}
+
+func (w *blockingWriter) Write(b []byte) (int, error) {
+	if !w.once {
+		w.once = true
+		close(w.started)
+		<-w.release
+	}
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestBroker(t *testing.T) {
+	t.Run("drops slow consumers", func(t *testing.T) {
+		broker := sse.NewBroker().BufferSize(1).KeepAlive(0)
+		_ = broker.PublishPayload("topic", sse.Payload{ID: "", Event: "", Retry: 0, Data: "1"})
+
+		w := &blockingWriter{
+			ResponseRecorder: httptest.NewRecorder(),
+			started:          make(chan struct{}),
+			release:          make(chan struct{}),
+			once:             false,
+		}
+		r := httptest.NewRequest(http.MethodGet, "/events", nil)
+		r.Header.Set("Last-Event-ID", "0")
+
+		done := make(chan error)
+		go func() {
+			done <- broker.Serve(w, r, "topic")
+		}()
+
+		// The client is subscribed, and stuck writing the replayed message.
+		<-w.started
+		for _, data := range []string{"2", "3", "4"} {
+			_ = broker.PublishPayload("topic", sse.Payload{ID: "", Event: "", Retry: 0, Data: data})
+		}
+		close(w.release)
+
+		if err := <-done; !errors.Is(err, sse.ErrSlowConsumer) {
+			t.Fatalf("got error %v, want ErrSlowConsumer", err)
+		}
+
+		want := "id: 1\ndata: 1\n\nid: 2\ndata: 2\n\n"
+		if got := w.Body.String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("buffer size", func(t *testing.T) {
+		for _, size := range []int{-1, 0, 1} {
+			broker := sse.NewBroker().BufferSize(size).KeepAlive(0)
+			_ = broker.PublishPayload("topic", sse.Payload{ID: "", Event: "", Retry: 0, Data: "1"})
+
+			w := &blockingWriter{
+				ResponseRecorder: httptest.NewRecorder(),
+				started:          make(chan struct{}),
+				release:          make(chan struct{}),
+				once:             false,
+			}
+			r := httptest.NewRequest(http.MethodGet, "/events", nil)
+			r.Header.Set("Last-Event-ID", "0")
+
+			done := make(chan error)
+			go func() {
+				done <- broker.Serve(w, r, "topic")
+			}()
+
+			// The client is subscribed and busy, so the next message is buffered.
+			<-w.started
+			_ = broker.PublishPayload("topic", sse.Payload{ID: "", Event: "", Retry: 0, Data: "2"})
+			broker.Close()
+			close(w.release)
+
+			if err := <-done; err != nil {
+				t.Fatalf("BufferSize(%d): got error %v, want nil", size, err)
+			}
+			want := "id: 1\ndata: 1\n\nid: 2\ndata: 2\n\n"
+			if got := w.Body.String(); got != want {
+				t.Errorf("BufferSize(%d): got %q, want %q", size, got, want)
+			}
+		}
+	})
+
+	t.Run("close disconnects clients", func(t *testing.T) {
+		broker := sse.NewBroker().KeepAlive(0)
+		_ = broker.PublishPayload("topic", sse.Payload{ID: "", Event: "", Retry: 0, Data: "1"})
+
+		w := &blockingWriter{
+			ResponseRecorder: httptest.NewRecorder(),
+			started:          make(chan struct{}),
+			release:          make(chan struct{}),
+			once:             false,
+		}
+		r := httptest.NewRequest(http.MethodGet, "/events", nil)
+		r.Header.Set("Last-Event-ID", "0")
+
+		done := make(chan error)
+		go func() {
+			done <- broker.Serve(w, r, "topic")
+		}()
+
+		<-w.started
+		broker.Close()
+		close(w.release)
+
+		if err := <-done; err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if err := broker.Publish(context.Background(), "topic", "", templ.Raw("2")); !errors.Is(err, sse.ErrBrokerClosed) {
+			t.Errorf("got error %v, want ErrBrokerClosed", err)
+		}
+		if got := w.Body.String(); strings.Contains(got, "data: 2") {
+			t.Errorf("got %q after close", got)
+		}
+	})
+}
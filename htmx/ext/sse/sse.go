@@ -1,20 +1,29 @@
 // package sse connects to an EventSource directly from HTML. It manages the connections to your web server, listens for server events, and then swaps their contents into your htmx webpage in real-time.
 //
+// On the server, a [Writer] streams rendered components to a single connection, and a [Broker] fans messages out to every connection subscribed to a topic.
+//
 // [EventSource]: https://developer.mozilla.org/en-US/docs/Web/API/Server-sent_events/Using_server-sent_events
 package sse
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
 )
 
 // Extension connects to an EventSource directly from HTML. It manages the connections to your web server, listens for server events, and then swaps their contents into your htmx webpage in real-time.
 //
 // # Install
 //
+// For htmx 1.x:
+//
 //	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/sse.js"></script>
 //
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-sse@2/sse.js"></script>
+//
 // Extension: [server-sent-events]
 //
 // [server-sent-events]: https://htmx.org/extensions/server-sent-events/
@@ -22,21 +31,57 @@ import (
 // [EventSource]: https://developer.mozilla.org/en-US/docs/Web/API/Server-sent_events/Using_server-sent_events
 const Extension htmx.Extension = "sse"
 
+// An Event is the name of a server-sent event.
+//
+// The same value can be used by the [Swap], [Close], and [Event.Trigger] attributes, and by the [Writer] and [Broker] that send the event, so the names always match.
+type Event string
+
 // Message is the the default name of an empty SSE event.
-const Message = "message"
+const Message Event = "message"
+
+// Trigger starts a builder chain for an hx-trigger that fires when the event is received, to be used with [htmx.HX.TriggerExtended]. This lets SSE messages trigger HTTP callbacks, with modifiers and filters, or alongside other triggers.
+//
+//	hx.TriggerExtended(sse.Event("update").Trigger().Delay(time.Second))
+func (e Event) Trigger() *trigger.Event {
+	return trigger.On(trigger.TriggerEvent("sse:" + string(e)))
+}
+
+// Lifecycle events dispatched on the element with [Connect], for use with [trigger.On] or hx-on.
+const (
+	OnOpen          trigger.TriggerEvent = "htmx:sseOpen"          // triggered when an EventSource connection is opened
+	OnError         trigger.TriggerEvent = "htmx:sseError"         // triggered when an EventSource connection fails
+	OnBeforeMessage trigger.TriggerEvent = "htmx:sseBeforeMessage" // triggered before a message is swapped in. Calling preventDefault() cancels the swap
+	OnMessage       trigger.TriggerEvent = "htmx:sseMessage"       // triggered after a message is swapped in
+	OnClose         trigger.TriggerEvent = "htmx:sseClose"         // triggered when the EventSource is closed, by sse-close, or because the element was removed or replaced
+)
 
-// Connect
+// Connect connects to an EventSource at the URL, and starts listening for server-sent events.
+// Child elements can swap in messages with [Swap], or trigger requests with [Event.Trigger].
+//
+// The connection is closed when the element is removed from the page. To close it when the server sends an event, use [Close].
 func Connect[T any](hx htmx.HX[T], url string) T {
 	return hx.Attr("sse-connect", url)
 }
 
-// Swap
-func Swap[T any](hx htmx.HX[T], messageName string) T {
-	return hx.Attr("sse-swap", messageName)
+// Swap swaps the data of the named events into the element. Pass several events to swap in any of them.
+//
+// The swap strategy is set with [htmx.HX.Swap], and defaults to innerHTML.
+func Swap[T any](hx htmx.HX[T], events ...Event) T {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = string(event)
+	}
+	return hx.Attr("sse-swap", strings.Join(names, ","))
+}
+
+// Close closes the EventSource opened by [Connect] when the named event is received, so the server can end the stream without the browser reconnecting.
+func Close[T any](hx htmx.HX[T], event Event) T {
+	return hx.Attr("sse-close", string(event))
 }
 
 // Trigger allows SSE messages to trigger HTTP callbacks using the [htmx.HX.Trigger()] attribute.
-func Trigger[T any](hx htmx.HX[T], event string) T {
-	prefixedEvent := fmt.Sprintf("sse:%s", event)
-	return hx.Attr(htmx.Trigger, prefixedEvent)
+//
+// To add modifiers, or combine it with other triggers, use [Event.Trigger] with [htmx.HX.TriggerExtended].
+func Trigger[T any](hx htmx.HX[T], event Event) T {
+	return hx.Attr(htmx.Trigger, event.Trigger().String())
 }
@@ -2,9 +2,11 @@ package sse_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/will-wow/typed-htmx-go/htmx"
 	"github.com/will-wow/typed-htmx-go/htmx/ext/sse"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
 )
 
 var hx = htmx.NewStringAttrs()
@@ -38,3 +40,30 @@ func ExampleTrigger() {
 	fmt.Println(attr)
 	// Output: hx-trigger='sse:event'
 }
+
+func ExampleSwap_multiple() {
+	attr := sse.Swap(hx, "created", "updated")
+	fmt.Println(attr)
+	// Output: sse-swap='created,updated'
+}
+
+func ExampleClose() {
+	attr := sse.Close(hx, "done")
+	fmt.Println(attr)
+	// Output: sse-close='done'
+}
+
+func ExampleEvent_Trigger() {
+	attr := hx.TriggerExtended(
+		sse.Event("update").Trigger().Delay(time.Second),
+		trigger.On("click"),
+	)
+	fmt.Println(attr)
+	// Output: hx-trigger='sse:update delay:1s, click'
+}
+
+func ExampleOnOpen() {
+	attr := hx.TriggerExtended(trigger.On(sse.OnOpen).Once())
+	fmt.Println(attr)
+	// Output: hx-trigger='htmx:sseOpen once'
+}
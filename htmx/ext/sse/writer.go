@@ -0,0 +1,163 @@
+package sse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+)
+
+// ErrFlushNotSupported is returned by [NewWriter] when the response can't be flushed, so events can't be streamed.
+var ErrFlushNotSupported = errors.New("sse: response writer does not support flushing")
+
+// ErrInvalidField is returned when an event id or name contains a newline, which would break the event stream.
+var ErrInvalidField = errors.New("sse: id and event must not contain newlines")
+
+// A Payload is a single message in an event stream.
+type Payload struct {
+	ID    string        // sets the EventSource's last event id, sent back in the Last-Event-ID header when reconnecting
+	Event Event         // the event name, used by sse-swap and hx-trigger. Empty for the default "message" event
+	Retry time.Duration // how long the EventSource should wait before reconnecting. Zero to leave unchanged
+	Data  string        // the message body. Multi-line data is split across data: lines
+}
+
+// Node adapts a g.Node to a templ.Component, so gomponents nodes can be passed to [Writer.Render] and [Broker.Publish].
+//
+//	_ = stream.Render("countdown", sse.Node(html.Div(g.Text("3"))))
+func Node(node g.Node) templ.Component {
+	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		return node.Render(w)
+	})
+}
+
+// A Writer streams events to an EventSource, such as one opened by [Connect].
+// It is safe for concurrent use.
+type Writer struct {
+	mu  sync.Mutex
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	ctx context.Context
+}
+
+// NewWriter sets the event stream headers, and flushes them to the client.
+// Components are rendered with the request context.
+//
+// If the response can't be flushed, it returns [ErrFlushNotSupported] without writing anything, so the handler can still respond with an error.
+func NewWriter(w http.ResponseWriter, r *http.Request) (*Writer, error) {
+	rc := http.NewResponseController(w)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+
+	if err := rc.Flush(); err != nil {
+		for _, name := range []string{"Content-Type", "Cache-Control", "Connection", "X-Accel-Buffering"} {
+			h.Del(name)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrFlushNotSupported, err)
+	}
+
+	return &Writer{
+		mu:  sync.Mutex{},
+		w:   w,
+		rc:  rc,
+		ctx: r.Context(),
+	}, nil
+}
+
+// Send writes a message to the stream, and flushes it to the client.
+func (s *Writer) Send(p Payload) error {
+	var buf bytes.Buffer
+	if err := writePayload(&buf, p); err != nil {
+		return err
+	}
+	return s.write(buf.Bytes())
+}
+
+// Render renders a templ component, or a g.Node wrapped with [Node], and sends it as the data of a message with the given event name.
+// Use an empty event name, or [Message], for the default event.
+//
+// To set an id or retry hint, render the content with [RenderString], and use [Writer.Send].
+func (s *Writer) Render(event Event, component templ.Component) error {
+	data, err := RenderString(s.ctx, component)
+	if err != nil {
+		return err
+	}
+	return s.Send(Payload{ID: "", Event: event, Retry: 0, Data: data})
+}
+
+// Retry tells the EventSource how long to wait before reconnecting, without sending a message.
+func (s *Writer) Retry(d time.Duration) error {
+	return s.write([]byte("retry: " + strconv.FormatInt(d.Milliseconds(), 10) + "\n\n"))
+}
+
+// Comment writes a comment line, which the EventSource ignores.
+// Sending a comment periodically keeps idle connections from being closed by proxies.
+func (s *Writer) Comment(text string) error {
+	var buf bytes.Buffer
+	for _, line := range splitLines(text) {
+		_, _ = buf.WriteString(": " + line + "\n")
+	}
+	_ = buf.WriteByte('\n')
+	return s.write(buf.Bytes())
+}
+
+// write writes a complete block to the response and flushes it.
+func (s *Writer) write(b []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(b); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// RenderString renders a templ component, or a g.Node wrapped with [Node], to a string, to be used as [Payload] data.
+func RenderString(ctx context.Context, component templ.Component) (string, error) {
+	var buf strings.Builder
+	if err := component.Render(ctx, &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writePayload writes a message in the event stream format.
+func writePayload(buf *bytes.Buffer, p Payload) error {
+	if strings.ContainsAny(p.ID, "\r\n") || strings.ContainsAny(string(p.Event), "\r\n") {
+		return ErrInvalidField
+	}
+
+	if p.ID != "" {
+		_, _ = buf.WriteString("id: " + p.ID + "\n")
+	}
+	if p.Event != "" {
+		_, _ = buf.WriteString("event: " + string(p.Event) + "\n")
+	}
+	if p.Retry > 0 {
+		_, _ = buf.WriteString("retry: " + strconv.FormatInt(p.Retry.Milliseconds(), 10) + "\n")
+	}
+	for _, line := range splitLines(p.Data) {
+		_, _ = buf.WriteString("data: " + line + "\n")
+	}
+	_ = buf.WriteByte('\n')
+	return nil
+}
+
+// splitLines splits text on any of the line endings allowed in an event stream.
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.Split(text, "\n")
+}
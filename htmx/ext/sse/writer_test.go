@@ -0,0 +1,143 @@
+package sse_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+	"github.com/maragudk/gomponents/html"
+
+	"github.com/will-wow/typed-htmx-go/htmx/ext/sse"
+)
+
+func ExampleWriter_Render() {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+
+	stream, err := sse.NewWriter(w, r)
+	if err != nil {
+		return
+	}
+
+	_ = stream.Render("countdown", sse.Node(html.Div(g.Text("3"))))
+	_ = stream.Render("countdown", templ.Raw("<ul>\n<li>2</li>\n</ul>"))
+
+	fmt.Println(w.Header().Get("Content-Type"))
+	fmt.Print(w.Body.String())
+	// Output:
+	// text/event-stream
+	// event: countdown
+	// data: <div>3</div>
+	//
+	// event: countdown
+	// data: <ul>
+	// data: <li>2</li>
+	// data: </ul>
+}
+
+func ExampleRenderString() {
+	data, _ := sse.RenderString(context.Background(), sse.Node(html.P(g.Text("Updated"))))
+
+	w := httptest.NewRecorder()
+	stream, _ := sse.NewWriter(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+	_ = stream.Send(sse.Payload{ID: "7", Event: "update", Retry: 0, Data: data})
+
+	fmt.Print(w.Body.String())
+	// Output:
+	// id: 7
+	// event: update
+	// data: <p>Updated</p>
+}
+
+func ExampleWriter_Send() {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+
+	stream, _ := sse.NewWriter(w, r)
+
+	_ = stream.Send(sse.Payload{
+		ID:    "42",
+		Event: "update",
+		Retry: 5 * time.Second,
+		Data:  "<p>Updated</p>",
+	})
+
+	fmt.Print(w.Body.String())
+	// Output:
+	// id: 42
+	// event: update
+	// retry: 5000
+	// data: <p>Updated</p>
+}
+
+func ExampleWriter_Comment() {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+
+	stream, _ := sse.NewWriter(w, r)
+
+	_ = stream.Comment("keep-alive")
+	_ = stream.Retry(time.Second)
+
+	fmt.Print(w.Body.String())
+	// Output:
+	// : keep-alive
+	//
+	// retry: 1000
+}
+
+// noFlushWriter hides the Flush method of the underlying recorder.
+type noFlushWriter struct {
+	http.ResponseWriter
+}
+
+func TestNewWriter(t *testing.T) {
+	t.Run("flush not supported", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/events", nil)
+
+		_, err := sse.NewWriter(noFlushWriter{ResponseWriter: rec}, r)
+		if !errors.Is(err, sse.ErrFlushNotSupported) {
+			t.Fatalf("got error %v, want ErrFlushNotSupported", err)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "" {
+			t.Errorf("got Content-Type %q, want it unset", got)
+		}
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		stream, err := sse.NewWriter(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = stream.Send(sse.Payload{ID: "1\n2", Event: "", Retry: 0, Data: ""})
+		if !errors.Is(err, sse.ErrInvalidField) {
+			t.Errorf("got error %v, want ErrInvalidField", err)
+		}
+	})
+
+	t.Run("render error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		stream, err := sse.NewWriter(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		errRender := errors.New("render failed")
+		err = stream.Render("", sse.Node(g.NodeFunc(func(io.Writer) error { return errRender })))
+		if !errors.Is(err, errRender) {
+			t.Errorf("got error %v, want the render error", err)
+		}
+		if got := w.Body.String(); got != "" {
+			t.Errorf("got %q, want nothing sent", got)
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package ws
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/will-wow/typed-htmx-go/htmx/oob"
+)
+
+// ErrProtocol is returned when the peer sends a frame that breaks the WebSocket protocol. The connection is closed.
+var ErrProtocol = errors.New("ws: protocol error")
+
+// ErrMessageTooLarge is returned when a message is larger than the connection's read limit. The connection is closed.
+var ErrMessageTooLarge = errors.New("ws: message too large")
+
+// DefaultReadLimit is the largest message a connection will read, unless changed with [Conn.SetReadLimit].
+const DefaultReadLimit = 1 << 20
+
+// opcode is the type of a WebSocket frame.
+type opcode byte
+
+const (
+	opContinuation opcode = 0x0
+	opText         opcode = 0x1
+	opBinary       opcode = 0x2
+	opClose        opcode = 0x8
+	opPing         opcode = 0x9
+	opPong         opcode = 0xA
+)
+
+// Close status codes used by the connection.
+const (
+	closeNormal          = 1000
+	closeProtocolError   = 1002
+	closeInvalidData     = 1007
+	closeMessageTooLarge = 1009
+)
+
+// validCloseCode checks if a close status code may be sent in a close frame.
+// 1005, 1006 and 1015 are reserved for reporting closes locally, and codes below 1000 are unused. See RFC 6455, section 7.4.
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 3000 && code <= 4999:
+		return true
+	case code >= 1000 && code <= 1014:
+		return code != 1004 && code != 1005 && code != 1006
+	default:
+		return false
+	}
+}
+
+// A Conn is an open WebSocket connection, from either [Upgrade] on the server, or [Dial] on the client.
+//
+// Reads must be made from a single goroutine. Writes are safe for concurrent use.
+type Conn struct {
+	conn      net.Conn
+	br        *bufio.Reader
+	client    bool
+	readLimit int64
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newConn(conn net.Conn, br *bufio.Reader, client bool) *Conn {
+	return &Conn{
+		conn:      conn,
+		br:        br,
+		client:    client,
+		readLimit: DefaultReadLimit,
+		mu:        sync.Mutex{},
+		closed:    false,
+	}
+}
+
+// SetReadLimit sets the largest message, in bytes, that the connection will read.
+func (c *Conn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
+
+// ReadText reads the next text or binary message.
+// Pings are answered automatically. When the peer closes the connection, it returns [io.EOF].
+func (c *Conn) ReadText() ([]byte, error) {
+	var message []byte
+	var messageOp opcode
+
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		// Control frames can arrive between the fragments of a message, so only data frames finish one.
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			code := closeNormal
+			if len(payload) == 1 {
+				return nil, c.fail(closeProtocolError, fmt.Errorf("%w: bad close frame", ErrProtocol))
+			}
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+				if !validCloseCode(code) {
+					return nil, c.fail(closeProtocolError, fmt.Errorf("%w: invalid close code %d", ErrProtocol, code))
+				}
+			}
+			c.closeWith(code)
+			return nil, io.EOF
+		case opText, opBinary:
+			if messageOp != 0 {
+				return nil, c.fail(closeProtocolError, ErrProtocol)
+			}
+			messageOp = op
+			message = payload
+			if fin {
+				return c.finishMessage(messageOp, message)
+			}
+		case opContinuation:
+			if messageOp == 0 {
+				return nil, c.fail(closeProtocolError, ErrProtocol)
+			}
+			if int64(len(message)+len(payload)) > c.readLimit {
+				return nil, c.fail(closeMessageTooLarge, ErrMessageTooLarge)
+			}
+			message = append(message, payload...)
+			if fin {
+				return c.finishMessage(messageOp, message)
+			}
+		default:
+			return nil, c.fail(closeProtocolError, ErrProtocol)
+		}
+	}
+}
+
+// finishMessage checks a complete message before it's returned.
+func (c *Conn) finishMessage(op opcode, message []byte) ([]byte, error) {
+	if op == opText && !utf8.Valid(message) {
+		return nil, c.fail(closeInvalidData, ErrProtocol)
+	}
+	return message, nil
+}
+
+// ReadMessage reads the next message, and decodes it as a [Message] sent by an element with [Send].
+func (c *Conn) ReadMessage() (Message, error) {
+	data, err := c.ReadText()
+	if err != nil {
+		return Message{}, err
+	}
+	return ParseMessage(data)
+}
+
+// WriteText sends a text message.
+func (c *Conn) WriteText(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// WriteMessage sends a [Message] encoded like the ws extension does, to act as a browser in tests.
+func (c *Conn) WriteMessage(m Message) error {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return c.WriteText(data)
+}
+
+// Send renders the fragments, with hx-swap-oob injected into each root element, and sends them as a single message.
+// The ws extension swaps every element in the message into the element on the page with the same id, or as set by the fragment's strategy and target.
+func (c *Conn) Send(ctx context.Context, fragments ...*oob.Fragment) error {
+	var buf bytes.Buffer
+	if err := oob.NewTempl(nil, fragments...).Render(ctx, &buf); err != nil {
+		return err
+	}
+	return c.WriteText(buf.Bytes())
+}
+
+// Close sends a close message to the peer, and closes the connection.
+func (c *Conn) Close() error {
+	return c.closeWith(closeNormal)
+}
+
+// fail closes the connection with a status code, and returns err.
+func (c *Conn) fail(code int, err error) error {
+	_ = c.closeWith(code)
+	return err
+}
+
+// closeWith sends a close frame with a status code, and closes the connection.
+func (c *Conn) closeWith(code int) error {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	_ = c.writeFrame(opClose, payload)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
+
+// readFrame reads a single frame, and unmasks its payload.
+func (c *Conn) readFrame() (fin bool, op opcode, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(closeProtocolError, fmt.Errorf("%w: reserved bits set", ErrProtocol))
+	}
+	op = opcode(header[0] & 0x0F)
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+
+	// Clients must mask every frame, and servers must not.
+	if masked == c.client {
+		return false, 0, nil, c.fail(closeProtocolError, fmt.Errorf("%w: bad masking", ErrProtocol))
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	isControl := op&0x8 != 0
+	if isControl && (length > 125 || !fin) {
+		return false, 0, nil, c.fail(closeProtocolError, fmt.Errorf("%w: bad control frame", ErrProtocol))
+	}
+	if length < 0 || length > c.readLimit {
+		return false, 0, nil, c.fail(closeMessageTooLarge, ErrMessageTooLarge)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame writes a single, final frame. Client frames are masked.
+func (c *Conn) writeFrame(op opcode, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return net.ErrClosed
+	}
+
+	frame := make([]byte, 0, len(payload)+14)
+	frame = append(frame, 0x80|byte(op))
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		frame = append(frame, maskBit|byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		maskBytes(mask, frame[start:])
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// maskBytes applies a masking key to a payload, in place.
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
@@ -0,0 +1,130 @@
+package ws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/will-wow/typed-htmx-go/htmx/hxreq"
+)
+
+// headersKey is the key of the htmx request headers in a message.
+const headersKey = "HEADERS"
+
+// A Message is sent by an element with [Send].
+// It holds the element's values, like an htmx request body, and the htmx request headers.
+type Message struct {
+	Headers hxreq.Headers // the htmx request headers, including the id of the triggering element
+	Values  url.Values    // the values of the form, and any hx-vals or hx-include values
+}
+
+// ParseMessage decodes a JSON message sent by the ws extension.
+//
+// String values are kept as-is, arrays of values from multi-select inputs become multiple values, and any other JSON value is kept as its JSON text.
+func ParseMessage(data []byte) (Message, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Message{}, fmt.Errorf("ws: invalid message: %w", err)
+	}
+
+	msg := Message{
+		Headers: hxreq.Headers{},
+		Values:  url.Values{},
+	}
+
+	for key, value := range raw {
+		if key == headersKey {
+			headers, err := parseHeaders(value)
+			if err != nil {
+				return Message{}, err
+			}
+			msg.Headers = headers
+			continue
+		}
+		msg.Values[key] = parseValues(value)
+	}
+
+	return msg, nil
+}
+
+// parseHeaders decodes the HEADERS block into htmx request headers.
+func parseHeaders(data json.RawMessage) (hxreq.Headers, error) {
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return hxreq.Headers{}, fmt.Errorf("ws: invalid %s: %w", headersKey, err)
+	}
+
+	h := http.Header{}
+	for name, value := range values {
+		if value == nil {
+			continue
+		}
+		h.Set(name, fmt.Sprint(value))
+	}
+	return hxreq.ParseHeader(h), nil
+}
+
+// parseValues decodes a form value, which may be a single value or an array.
+func parseValues(data json.RawMessage) []string {
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return []string{parseValue(data)}
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = parseValue(value)
+	}
+	return result
+}
+
+// parseValue returns a JSON string as-is, or the JSON text of any other value.
+func parseValue(data json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s
+	}
+	return string(bytes.TrimSpace(data))
+}
+
+// MarshalJSON encodes the message the same way the ws extension does, with each value as a string, or an array for multiple values.
+// Only set headers are included, and keys are sorted.
+func (m Message) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]any, len(m.Values)+1)
+	for key, values := range m.Values {
+		if len(values) == 1 {
+			obj[key] = values[0]
+		} else {
+			obj[key] = values
+		}
+	}
+	obj[headersKey] = headerValues(m.Headers)
+	return json.Marshal(obj)
+}
+
+// headerValues returns the htmx request headers that are set.
+func headerValues(headers hxreq.Headers) map[string]string {
+	values := map[string]string{}
+	set := func(name hxreq.Header, value string) {
+		if value != "" {
+			values[string(name)] = value
+		}
+	}
+	setBool := func(name hxreq.Header, value bool) {
+		if value {
+			values[string(name)] = "true"
+		}
+	}
+
+	setBool(hxreq.Request, headers.Request)
+	setBool(hxreq.Boosted, headers.Boosted)
+	set(hxreq.CurrentURL, headers.CurrentURL)
+	setBool(hxreq.HistoryRestoreRequest, headers.HistoryRestoreRequest)
+	set(hxreq.Prompt, headers.Prompt)
+	set(hxreq.Target, headers.Target)
+	set(hxreq.TriggerName, headers.TriggerName)
+	set(hxreq.Trigger, headers.Trigger)
+	return values
+}
@@ -0,0 +1,153 @@
+package ws
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ErrBadHandshake is returned when a request or response is not a valid WebSocket handshake.
+var ErrBadHandshake = errors.New("ws: bad handshake")
+
+// ErrBadOrigin is returned by [Upgrader.Upgrade] when the request comes from an origin that is not allowed.
+var ErrBadOrigin = errors.New("ws: origin not allowed")
+
+// acceptGUID is appended to the client's key to build the accept header, as defined by RFC 6455.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// An Upgrader accepts WebSocket connections.
+//
+// To protect against cross-site WebSocket hijacking, it only accepts browser requests from the same origin as the request host, unless other origins are allowed with [Upgrader.AllowOrigins].
+type Upgrader struct {
+	origins []string
+}
+
+// NewUpgrader starts a builder chain for accepting WebSocket connections.
+func NewUpgrader() *Upgrader {
+	return &Upgrader{
+		origins: nil,
+	}
+}
+
+// AllowOrigins allows connections from pages served from other origins, like "https://example.com". Pass "*" to allow any origin.
+func (u *Upgrader) AllowOrigins(origins ...string) *Upgrader {
+	u.origins = append(u.origins, origins...)
+	return u
+}
+
+// Upgrade accepts a WebSocket connection from a same-origin page. See [Upgrader.Upgrade].
+//
+//	mux.HandleFunc("GET /chat", func(w http.ResponseWriter, r *http.Request) {
+//		conn, err := ws.Upgrade(w, r)
+//		if err != nil {
+//			return
+//		}
+//		defer conn.Close()
+//
+//		for {
+//			msg, err := conn.ReadMessage()
+//			if err != nil {
+//				return
+//			}
+//			_ = conn.Send(r.Context(), oob.Templ(chatMessage(msg.Values.Get("message"))).Strategy(swap.BeforeEnd).Target("#messages"))
+//		}
+//	})
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	return NewUpgrader().Upgrade(w, r)
+}
+
+// Upgrade checks the WebSocket handshake, and takes over the connection from the HTTP server.
+//
+// If the handshake is invalid, it responds with an HTTP error and returns [ErrBadHandshake] or [ErrBadOrigin].
+// After a successful upgrade, the handler must not use w, and should close the connection when it's done.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	switch {
+	case r.Method != http.MethodGet:
+		return nil, u.reject(w, http.StatusMethodNotAllowed, "method must be GET")
+	case !headerContains(r.Header, "Connection", "upgrade"):
+		return nil, u.reject(w, http.StatusBadRequest, "missing Connection: upgrade")
+	case !headerContains(r.Header, "Upgrade", "websocket"):
+		return nil, u.reject(w, http.StatusBadRequest, "missing Upgrade: websocket")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, u.reject(w, http.StatusUpgradeRequired, "unsupported version")
+	case key == "":
+		return nil, u.reject(w, http.StatusBadRequest, "missing Sec-WebSocket-Key")
+	case !validKey(key):
+		return nil, u.reject(w, http.StatusBadRequest, "Sec-WebSocket-Key must be 16 bytes, base64 encoded")
+	}
+
+	if !u.allowOrigin(r) {
+		http.Error(w, ErrBadOrigin.Error(), http.StatusForbidden)
+		return nil, ErrBadOrigin
+	}
+
+	netConn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("ws: hijack: %w", err)
+	}
+
+	_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	_, _ = rw.WriteString("Upgrade: websocket\r\n")
+	_, _ = rw.WriteString("Connection: Upgrade\r\n")
+	_, _ = rw.WriteString("Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+
+	return newConn(netConn, rw.Reader, false), nil
+}
+
+// reject responds with an HTTP error, and returns ErrBadHandshake.
+func (u *Upgrader) reject(w http.ResponseWriter, status int, reason string) error {
+	http.Error(w, reason, status)
+	return fmt.Errorf("%w: %s", ErrBadHandshake, reason)
+}
+
+// allowOrigin checks if the request's Origin is the request host, or an allowed origin.
+// Requests without an Origin header don't come from a browser, and are allowed.
+func (u *Upgrader) allowOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(u.origins, "*") || slices.Contains(u.origins, origin) {
+		return true
+	}
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(originURL.Host, r.Host)
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value for a client's key.
+func acceptKey(key string) string {
+	h := sha1.New()
+	_, _ = h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// validKey checks that a client's key is a base64 encoded, 16 byte value, as required by RFC 6455.
+func validKey(key string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	return err == nil && len(decoded) == 16
+}
+
+// headerContains checks if a comma-separated header contains a token, ignoring case.
+func headerContains(h http.Header, name string, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
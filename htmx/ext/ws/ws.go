@@ -0,0 +1,64 @@
+// package ws connects to a WebSocket directly from HTML. It manages the connection to your web server, sends form values with the htmx request headers, and swaps the HTML fragments sent back by the server into your webpage out-of-band.
+//
+// On the server, [Upgrade] accepts the connection, [Conn.ReadMessage] decodes the messages sent by [Send] elements, and [Conn.Send] pushes rendered [oob.Fragment] values back.
+// [Dial] opens a client connection, to test an endpoint without a browser.
+//
+// [WebSocket]: https://developer.mozilla.org/en-US/docs/Web/API/WebSockets_API
+package ws
+
+import (
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+)
+
+// Extension connects to a WebSocket directly from HTML. It manages the connection to your web server, sends form values with the htmx request headers, and swaps the HTML fragments sent back by the server into your webpage out-of-band.
+//
+// The reconnection delay and binary type can be set with [hxconfig.Builder.WSReconnectDelay] and [hxconfig.Builder.WSBinaryType].
+//
+// # Install
+//
+// For htmx 1.x:
+//
+//	<script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/ws.js"></script>
+//
+// For htmx 2.x, extensions are published as separate packages:
+//
+//	<script src="https://unpkg.com/htmx-ext-ws@2/ws.js"></script>
+//
+// Extension: [web-sockets]
+//
+// [web-sockets]: https://htmx.org/extensions/web-sockets/
+const Extension htmx.Extension = "ws"
+
+// Connect establishes a WebSocket connection to the URL, which may be relative or use the ws:// or wss:// scheme.
+// The connection is closed when the element is removed from the page, and reconnects with exponential back-off if it drops.
+//
+// Extension: [web-sockets]
+//
+// [web-sockets]: https://htmx.org/extensions/web-sockets/
+func Connect[T any](hx htmx.HX[T], url string) T {
+	return hx.Attr("ws-connect", url)
+}
+
+// Send sends a message to the nearest WebSocket opened with [Connect] when the element is triggered.
+// On a form, the message contains the form values, along with a HEADERS block of the htmx request headers. See [Message].
+//
+// Extension: [web-sockets]
+//
+// [web-sockets]: https://htmx.org/extensions/web-sockets/
+func Send[T any](hx htmx.HX[T]) T {
+	return hx.Attr("ws-send", true)
+}
+
+// Lifecycle events dispatched on the element with [Connect], for use with [trigger.On] or hx-on.
+const (
+	OnConnecting    trigger.TriggerEvent = "htmx:wsConnecting"    // triggered when a connection is about to be opened
+	OnOpen          trigger.TriggerEvent = "htmx:wsOpen"          // triggered when a connection is opened
+	OnClose         trigger.TriggerEvent = "htmx:wsClose"         // triggered when a connection is closed
+	OnError         trigger.TriggerEvent = "htmx:wsError"         // triggered when the connection fails
+	OnBeforeMessage trigger.TriggerEvent = "htmx:wsBeforeMessage" // triggered before a message from the server is swapped in. Calling preventDefault() cancels the swap
+	OnAfterMessage  trigger.TriggerEvent = "htmx:wsAfterMessage"  // triggered after a message from the server is swapped in
+	OnConfigSend    trigger.TriggerEvent = "htmx:wsConfigSend"    // triggered before a message is sent, to modify its values and headers
+	OnBeforeSend    trigger.TriggerEvent = "htmx:wsBeforeSend"    // triggered just before a message is sent. Calling preventDefault() cancels it
+	OnAfterSend     trigger.TriggerEvent = "htmx:wsAfterSend"     // triggered after a message is sent
+)
@@ -0,0 +1,351 @@
+package ws_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/ws"
+	"github.com/will-wow/typed-htmx-go/htmx/hxreq"
+	"github.com/will-wow/typed-htmx-go/htmx/oob"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+var hx = htmx.NewStringAttrs()
+
+func ExampleExtension() {
+	attr := hx.Ext(ws.Extension)
+	fmt.Println(attr)
+	// Output: hx-ext='ws'
+}
+
+func ExampleConnect() {
+	attr := ws.Connect(hx, "/chatroom")
+	fmt.Println(attr)
+	// Output: ws-connect='/chatroom'
+}
+
+func ExampleSend() {
+	attr := ws.Send(hx)
+	fmt.Println(attr)
+	// Output: ws-send
+}
+
+func ExampleParseMessage() {
+	msg, _ := ws.ParseMessage([]byte(`{
+		"message": "Hello",
+		"tags": ["a", "b"],
+		"HEADERS": {
+			"HX-Request": "true",
+			"HX-Trigger": "chat-form",
+			"HX-Trigger-Name": null,
+			"HX-Target": "chat-form",
+			"HX-Current-URL": "http://localhost/chat"
+		}
+	}`))
+
+	fmt.Println(msg.Values.Get("message"), msg.Values["tags"])
+	fmt.Println(msg.Headers.Request, msg.Headers.Trigger, msg.Headers.CurrentURL)
+	// Output:
+	// Hello [a b]
+	// true chat-form http://localhost/chat
+}
+
+func ExampleDial() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			fragment := templ.Raw(fmt.Sprintf(`<li>%s</li>`, msg.Values.Get("message")))
+			_ = conn.Send(r.Context(), oob.Templ(fragment).Strategy(swap.BeforeEnd).Target("#messages"))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	conn, err := ws.Dial(ctx, srv.URL, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer conn.Close()
+
+	_ = conn.WriteMessage(ws.Message{
+		Headers: hxreq.Headers{
+			Request:               true,
+			Boosted:               false,
+			CurrentURL:            "http://localhost/chat",
+			HistoryRestoreRequest: false,
+			Prompt:                "",
+			Target:                "chat-form",
+			TriggerName:           "",
+			Trigger:               "chat-form",
+		},
+		Values: url.Values{"message": {"Hello"}},
+	})
+
+	data, _ := conn.ReadText()
+	fmt.Println(string(data))
+	// Output: <template><li hx-swap-oob="beforeend:#messages">Hello</li></template>
+}
+
+// echoServer starts a server that echoes every message back, using the upgrader and read limit.
+func echoServer(t *testing.T, upgrader *ws.Upgrader, readLimit int64, errs chan<- error) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetReadLimit(readLimit)
+
+		for {
+			data, err := conn.ReadText()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if err := conn.WriteText(data); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// rawServer starts a server that completes the handshake, then writes raw, unmasked frames.
+// If received is not nil, everything the client sends is passed to it once the client closes the connection.
+func rawServer(t *testing.T, received chan<- []byte, frames ...[]byte) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+		for _, frame := range frames {
+			_, _ = rw.Write(frame)
+		}
+		_ = rw.Flush()
+		// Wait for the client to finish reading.
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, rw)
+		if received != nil {
+			received <- buf.Bytes()
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func dial(t *testing.T, srv *httptest.Server, header http.Header) *ws.Conn {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := ws.Dial(ctx, strings.Replace(srv.URL, "http://", "ws://", 1), header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestConn(t *testing.T) {
+	t.Run("echoes messages of every length encoding", func(t *testing.T) {
+		errs := make(chan error, 1)
+		conn := dial(t, echoServer(t, ws.NewUpgrader(), ws.DefaultReadLimit, errs), nil)
+
+		for _, size := range []int{0, 125, 126, 65535, 70000} {
+			want := strings.Repeat("x", size)
+			if err := conn.WriteText([]byte(want)); err != nil {
+				t.Fatal(err)
+			}
+			got, err := conn.ReadText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want {
+				t.Errorf("size %d: got %d bytes back", size, len(got))
+			}
+		}
+	})
+
+	t.Run("close ends the server read", func(t *testing.T) {
+		errs := make(chan error, 1)
+		conn := dial(t, echoServer(t, ws.NewUpgrader(), ws.DefaultReadLimit, errs), nil)
+
+		if err := conn.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-errs; !errors.Is(err, io.EOF) {
+			t.Errorf("got error %v, want io.EOF", err)
+		}
+	})
+
+	t.Run("ping between fragments", func(t *testing.T) {
+		conn := dial(t, rawServer(t, nil,
+			// A text frame without fin, a ping, then the final continuation frame.
+			[]byte{0x01, 3, 'H', 'e', 'l'},
+			[]byte{0x89, 1, 'p'},
+			[]byte{0x80, 2, 'l', 'o'},
+		), nil)
+
+		got, err := conn.ReadText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "Hello" {
+			t.Errorf("got %q, want the whole message %q", got, "Hello")
+		}
+	})
+
+	t.Run("close codes", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			payload []byte
+			wantErr error
+			reply   uint16
+		}{
+			{name: "no code", payload: []byte{}, wantErr: io.EOF, reply: 1000},
+			{name: "normal", payload: []byte{0x03, 0xE8}, wantErr: io.EOF, reply: 1000},
+			{name: "application code with reason", payload: []byte{0x0F, 0xA0, 'b', 'y', 'e'}, wantErr: io.EOF, reply: 4000},
+			{name: "one byte", payload: []byte{0x03}, wantErr: ws.ErrProtocol, reply: 1002},
+			{name: "below 1000", payload: []byte{0x03, 0xE7}, wantErr: ws.ErrProtocol, reply: 1002},
+			{name: "no status received", payload: []byte{0x03, 0xED}, wantErr: ws.ErrProtocol, reply: 1002},
+			{name: "abnormal closure", payload: []byte{0x03, 0xEE}, wantErr: ws.ErrProtocol, reply: 1002},
+			{name: "tls handshake", payload: []byte{0x03, 0xF7}, wantErr: ws.ErrProtocol, reply: 1002},
+			{name: "unassigned", payload: []byte{0x13, 0x88}, wantErr: ws.ErrProtocol, reply: 1002},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				received := make(chan []byte, 1)
+				frame := append([]byte{0x88, byte(len(tt.payload))}, tt.payload...)
+				conn := dial(t, rawServer(t, received, frame), nil)
+
+				if _, err := conn.ReadText(); !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+
+				// The client's reply is a masked close frame, with a 2 byte code.
+				reply := <-received
+				if len(reply) != 8 || reply[0] != 0x88 || reply[1] != 0x82 {
+					t.Fatalf("got reply % x, want a close frame", reply)
+				}
+				code := binary.BigEndian.Uint16([]byte{reply[6] ^ reply[2], reply[7] ^ reply[3]})
+				if code != tt.reply {
+					t.Errorf("got close code %d, want %d", code, tt.reply)
+				}
+			})
+		}
+	})
+
+	t.Run("read limit", func(t *testing.T) {
+		errs := make(chan error, 1)
+		conn := dial(t, echoServer(t, ws.NewUpgrader(), 10, errs), nil)
+
+		if err := conn.WriteText([]byte(strings.Repeat("x", 20))); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-errs; !errors.Is(err, ws.ErrMessageTooLarge) {
+			t.Errorf("got server error %v, want ErrMessageTooLarge", err)
+		}
+		if _, err := conn.ReadText(); !errors.Is(err, io.EOF) {
+			t.Errorf("got client error %v, want io.EOF", err)
+		}
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	t.Run("rejects other origins", func(t *testing.T) {
+		errs := make(chan error, 1)
+		srv := echoServer(t, ws.NewUpgrader(), ws.DefaultReadLimit, errs)
+
+		_, err := ws.Dial(context.Background(), srv.URL, http.Header{"Origin": {"https://example.com"}})
+		if !errors.Is(err, ws.ErrBadHandshake) {
+			t.Errorf("got client error %v, want ErrBadHandshake", err)
+		}
+		if err := <-errs; !errors.Is(err, ws.ErrBadOrigin) {
+			t.Errorf("got server error %v, want ErrBadOrigin", err)
+		}
+	})
+
+	t.Run("allows listed origins", func(t *testing.T) {
+		errs := make(chan error, 1)
+		srv := echoServer(t, ws.NewUpgrader().AllowOrigins("https://example.com"), ws.DefaultReadLimit, errs)
+
+		dial(t, srv, http.Header{"Origin": {"https://example.com"}})
+	})
+
+	t.Run("allows same origin", func(t *testing.T) {
+		errs := make(chan error, 1)
+		srv := echoServer(t, ws.NewUpgrader(), ws.DefaultReadLimit, errs)
+
+		dial(t, srv, http.Header{"Origin": {srv.URL}})
+	})
+
+	t.Run("rejects invalid keys", func(t *testing.T) {
+		for _, key := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("only 8 b"))} {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/chat", nil)
+			r.Header.Set("Connection", "Upgrade")
+			r.Header.Set("Upgrade", "websocket")
+			r.Header.Set("Sec-WebSocket-Version", "13")
+			r.Header.Set("Sec-WebSocket-Key", key)
+
+			_, err := ws.Upgrade(w, r)
+			if !errors.Is(err, ws.ErrBadHandshake) {
+				t.Errorf("%q: got error %v, want ErrBadHandshake", key, err)
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%q: got status %d, want %d", key, w.Code, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("rejects plain requests", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/chat", nil)
+
+		_, err := ws.Upgrade(w, r)
+		if !errors.Is(err, ws.ErrBadHandshake) {
+			t.Errorf("got error %v, want ErrBadHandshake", err)
+		}
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
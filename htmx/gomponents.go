@@ -1,42 +1,134 @@
 package htmx
 
 import (
+	"fmt"
 	"io"
 	"strings"
 	"text/template"
 
+	"github.com/a-h/templ"
 	g "github.com/maragudk/gomponents"
 )
 
-func NewGomponents() HX[GomponentsAttrs] {
+func NewGomponents(opts ...Option) HX[GomponentsAttrs] {
 	return NewHX(
 		func(key Attribute, value any) GomponentsAttrs {
-			return GomponentsAttrs{key: key, value: value}
+			return GomponentsAttrs{key: key, value: value, group: nil}
 		},
+		opts...,
 	)
 }
 
+// GomponentsAttrs is an attribute node, or a group of them from [GomponentsGroup].
+// It renders every value type that templ renders, along with numbers and fmt.Stringer values.
+// Other values return an error when rendered.
 type GomponentsAttrs struct {
 	key   Attribute
 	value any
+	group []GomponentsAttrs
 }
 
-var _ g.Node = GomponentsAttrs{key: "", value: false}
+var _ g.Node = GomponentsAttrs{key: "", value: false, group: nil}
+
+// GomponentsGroup bundles several attributes into a single node, like [TemplAttrs] does for templ.
+// This is helpful for passing many attributes to a gomponents component.
+//
+// Unlike g.Group, the group is an attribute node: it's always rendered with the attributes of its parent element,
+// even when it's mixed in with the element's children, and can be rendered on its own.
+//
+// Attributes set more than once are merged like [TemplAttrs]. Use [MergeGomponentsAttrs] to find conflicts.
+func GomponentsGroup(attrs ...GomponentsAttrs) GomponentsAttrs {
+	group, _ := MergeGomponentsAttrs(attrs...)
+	return group
+}
+
+// MergeGomponentsAttrs bundles attributes like [GomponentsGroup], and returns an error wrapping [ErrConflict] for each attribute set to different values that can't be combined, like two hx-target attributes.
+func MergeGomponentsAttrs(attrs ...GomponentsAttrs) (GomponentsAttrs, error) {
+	var all []mergedAttr
+	for _, a := range attrs {
+		all = a.flatten(all)
+	}
+	merged, err := mergeAttrs(all)
+
+	group := make([]GomponentsAttrs, len(merged))
+	for i, attr := range merged {
+		group[i] = GomponentsAttrs{key: Attribute(attr.key), value: attr.value, group: nil}
+	}
+	return GomponentsAttrs{key: "", value: nil, group: group}, err
+}
+
+// flatten appends the attributes in a node, including nested groups, to a list.
+func (a GomponentsAttrs) flatten(attrs []mergedAttr) []mergedAttr {
+	if a.group != nil {
+		for _, attr := range a.group {
+			attrs = attr.flatten(attrs)
+		}
+		return attrs
+	}
+	if a.key == "" {
+		return attrs
+	}
+	return append(attrs, mergedAttr{key: string(a.key), value: a.value})
+}
 
 func (a GomponentsAttrs) Render(w io.Writer) error {
-	switch v := a.value.(type) {
-	// For strings, print the key='value' pair.
-	case string:
-		_, err := w.Write([]byte(" " + string(a.key) + `="` + template.HTMLEscapeString(v) + `"`))
+	if a.group != nil {
+		for _, attr := range a.group {
+			if err := attr.Render(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if a.key == "" {
+		return nil
+	}
+
+	value, ok, err := attrValue(a.value)
+	if err != nil {
+		return fmt.Errorf("htmx: rendering %s: %w", a.key, err)
+	}
+	if !ok {
+		return nil
+	}
+	key := template.HTMLEscapeString(string(a.key))
+	// Boolean attributes are rendered as just the key.
+	if value == nil {
+		_, err := io.WriteString(w, " "+key)
 		return err
-	// For booleans, print just the key if true.
+	}
+	_, err = io.WriteString(w, " "+key+`="`+template.HTMLEscapeString(*value)+`"`)
+	return err
+}
+
+// attrValue converts an attribute value to the text to render, following templ's rules for each type.
+// A nil text with ok set is a boolean attribute, and ok is false for attributes that shouldn't be rendered.
+func attrValue(value any) (text *string, ok bool, err error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		return &v, true, nil
+	case *string:
+		return v, v != nil, nil
 	case bool:
-		if v {
-			_, err := w.Write([]byte(" " + string(a.key)))
-			return err
-		}
+		return nil, v, nil
+	case *bool:
+		return nil, v != nil && *v, nil
+	case templ.KeyValue[string, bool]:
+		return &v.Key, v.Value, nil
+	case templ.KeyValue[bool, bool]:
+		return nil, v.Key && v.Value, nil
+	case func() bool:
+		return nil, v(), nil
+	case fmt.Stringer:
+		s := v.String()
+		return &s, true, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		s := fmt.Sprint(v)
+		return &s, true, nil
 	}
-	return nil
+	return nil, false, fmt.Errorf("unsupported value type %T", value)
 }
 
 // Type satisfies nodeTypeDescriber.
@@ -4,7 +4,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/a-h/templ"
 	g "github.com/maragudk/gomponents"
 	. "github.com/maragudk/gomponents/html"
 
@@ -43,7 +45,29 @@ func ExampleNewGomponents() {
 	// Output: <form hx-boost="true" hx-post="/submit" hx-swap="outerHTML" id="form"><input name="firstName"><button type="submit">Submit</button></form>
 }
 
+func ExampleGomponentsGroup() {
+	hx := htmx.NewGomponents()
+
+	submit := htmx.GomponentsGroup(
+		hx.Post("/submit"),
+		hx.Swap(swap.OuterHTML),
+	)
+
+	component := FormEl(
+		ID("form"),
+		Button(Type("submit"), g.Text("Submit")),
+		// The group is rendered with the form's attributes, even after its children.
+		submit,
+	)
+
+	_ = component.Render(os.Stdout)
+	// Output: <form id="form" hx-post="/submit" hx-swap="outerHTML"><button type="submit">Submit</button></form>
+}
+
 func TestRender(t *testing.T) {
+	text := "text"
+	yes := true
+
 	tests := []struct {
 		name string
 		node htmx.GomponentsAttrs
@@ -64,6 +88,71 @@ func TestRender(t *testing.T) {
 			node: htmx.GomponentsAttrs{},
 			want: ``,
 		},
+		{
+			name: "escaped value",
+			node: gomHx.Vals(map[string]any{"q": "<b>"}),
+			want: ` hx-vals="{&#34;q&#34;:&#34;\u003cb\u003e&#34;}"`,
+		},
+		{
+			name: "int value",
+			node: gomHx.Attr("hx-history-cache-size", 10),
+			want: ` hx-history-cache-size="10"`,
+		},
+		{
+			name: "float value",
+			node: gomHx.Attr("data-threshold", 0.5),
+			want: ` data-threshold="0.5"`,
+		},
+		{
+			name: "stringer value",
+			node: gomHx.Attr("data-delay", time.Second),
+			want: ` data-delay="1s"`,
+		},
+		{
+			name: "string pointer",
+			node: gomHx.Attr("data-text", &text),
+			want: ` data-text="text"`,
+		},
+		{
+			name: "nil string pointer",
+			node: gomHx.Attr("data-text", (*string)(nil)),
+			want: ``,
+		},
+		{
+			name: "bool pointer",
+			node: gomHx.Attr("data-yes", &yes),
+			want: ` data-yes`,
+		},
+		{
+			name: "false bool",
+			node: gomHx.Attr("data-no", false),
+			want: ``,
+		},
+		{
+			name: "templ key value",
+			node: gomHx.Attr("data-class", templ.KV("active", true)),
+			want: ` data-class="active"`,
+		},
+		{
+			name: "templ bool key value",
+			node: gomHx.Attr("data-flag", templ.KV(true, false)),
+			want: ``,
+		},
+		{
+			name: "bool func",
+			node: gomHx.Attr("data-func", func() bool { return true }),
+			want: ` data-func`,
+		},
+		{
+			name: "group",
+			node: htmx.GomponentsGroup(gomHx.Get("/a"), htmx.GomponentsGroup(gomHx.Preserve()), gomHx.Attr("data-no", false)),
+			want: ` hx-get="/a" hx-preserve`,
+		},
+		{
+			name: "empty group",
+			node: htmx.GomponentsGroup(),
+			want: ``,
+		},
 	}
 
 	for _, tt := range tests {
@@ -87,3 +176,14 @@ func TestRender(t *testing.T) {
 	}
 
 }
+
+func TestRender_unsupported(t *testing.T) {
+	var b strings.Builder
+	err := htmx.GomponentsGroup(gomHx.Get("/a"), gomHx.Attr("data-list", []int{1})).Render(&b)
+	if err == nil || err.Error() != "htmx: rendering data-list: unsupported value type []int" {
+		t.Errorf("got error %v", err)
+	}
+	if want := ` hx-get="/a"`; b.String() != want {
+		t.Errorf("got %s, want %s", b.String(), want)
+	}
+}
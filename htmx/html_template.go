@@ -0,0 +1,181 @@
+package htmx
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"time"
+
+	"github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+)
+
+// NewHTMLTemplate returns a HX instance for use with html/template.
+// Each attribute returns an escaped, double-quoted template.HTMLAttr, which html/template renders inside a tag as-is.
+//
+// Most templates should use the functions from [FuncMap] instead.
+func NewHTMLTemplate(opts ...Option) HX[template.HTMLAttr] {
+	return NewHX(func(k Attribute, v any) template.HTMLAttr {
+		value, ok, err := attrValue(v)
+		if err != nil || !ok {
+			return ""
+		}
+		key := template.HTMLEscapeString(string(k))
+		if value == nil {
+			return template.HTMLAttr(key)
+		}
+		return template.HTMLAttr(key + `="` + template.HTMLEscapeString(*value) + `"`)
+	}, opts...)
+}
+
+// FuncMap returns html/template functions for every [HX] attribute method, named with an hx prefix, like hxGet for [HX.Get].
+// Arguments are converted to the method's parameter types, so strategies, selectors and events can be passed as strings,
+// either as literals or from the template's data.
+//
+//	tmpl := template.Must(template.New("page").Funcs(htmx.FuncMap()).Parse(
+//		`<button {{ hxPost "/contacts" }} {{ hxTarget "closest tr" }} {{ hxConfirm "Are you sure?" }}>Save</button>`,
+//	))
+//
+// It also includes builders for the attributes that take them:
+//
+//	swap "outerHTML"         // swap.New().Strategy("outerHTML"), for hxSwapExtended
+//	trigger "click"          // trigger.On("click"), for hxTriggerExtended
+//	every (duration "1s")    // trigger.Every(time.Second), for hxTriggerExtended
+//	duration "500ms"         // time.ParseDuration("500ms")
+//	hxconfig                 // hxconfig.New(), for hxConfig
+//
+// Builder methods are chained with parentheses:
+//
+//	{{ hxSwapExtended ((swap "innerHTML").Transition) }}
+//	{{ hxTriggerExtended ((trigger "keyup").Changed.Delay (duration "500ms")) (trigger "search") }}
+//
+// Extension attributes, like those from the sse and classtools packages, aren't included, since those packages import htmx.
+// Add the ones a template needs with its own Funcs, or use hxAttr.
+func FuncMap(opts ...Option) template.FuncMap {
+	hx := NewHTMLTemplate(opts...)
+	return template.FuncMap{
+		"swap": convertArgs(func(strategy swap.Strategy) *swap.Builder {
+			return swap.New().Strategy(strategy)
+		}),
+		"trigger":  convertArgs(trigger.On),
+		"every":    convertArgs(trigger.Every),
+		"duration": time.ParseDuration,
+		"hxconfig": hxconfig.New,
+
+		// Attribute methods are listed explicitly, so that helpers like [HX.Reject] aren't published to templates.
+		"hxConfig":                convertArgs(hx.Config),
+		"hxBoost":                 convertArgs(hx.Boost),
+		"hxGet":                   convertArgs(hx.Get),
+		"hxPost":                  convertArgs(hx.Post),
+		"hxOn":                    convertArgs(hx.On),
+		"hxPushURL":               convertArgs(hx.PushURL),
+		"hxPushURLPath":           convertArgs(hx.PushURLPath),
+		"hxSelect":                convertArgs(hx.Select),
+		"hxSelectOOB":             convertArgs(hx.SelectOOB),
+		"hxSelectOOBWithStrategy": convertArgs(hx.SelectOOBWithStrategy),
+		"hxSwap":                  convertArgs(hx.Swap),
+		"hxSwapExtended":          convertArgs(hx.SwapExtended),
+		"hxSwapOOB":               convertArgs(hx.SwapOOB),
+		"hxSwapOOBWithStrategy":   convertArgs(hx.SwapOOBWithStrategy),
+		"hxSwapOOBSelector":       convertArgs(hx.SwapOOBSelector),
+		"hxTarget":                convertArgs(hx.Target),
+		"hxTrigger":               convertArgs(hx.Trigger),
+		"hxTriggerExtended":       convertArgs(hx.TriggerExtended),
+		"hxVals":                  convertArgs(hx.Vals),
+		"hxValsJS":                convertArgs(hx.ValsJS),
+		"hxConfirm":               convertArgs(hx.Confirm),
+		"hxDelete":                convertArgs(hx.Delete),
+		"hxDisable":               convertArgs(hx.Disable),
+		"hxDisabledElt":           convertArgs(hx.DisabledElt),
+		"hxDisinherit":            convertArgs(hx.Disinherit),
+		"hxDisinheritAll":         convertArgs(hx.DisinheritAll),
+		"hxInherit":               convertArgs(hx.Inherit),
+		"hxInheritAll":            convertArgs(hx.InheritAll),
+		"hxEncoding":              convertArgs(hx.Encoding),
+		"hxExt":                   convertArgs(hx.Ext),
+		"hxExtIgnore":             convertArgs(hx.ExtIgnore),
+		"hxHeaders":               convertArgs(hx.Headers),
+		"hxHeadersJS":             convertArgs(hx.HeadersJS),
+		"hxHistory":               convertArgs(hx.History),
+		"hxHistoryElt":            convertArgs(hx.HistoryElt),
+		"hxInclude":               convertArgs(hx.Include),
+		"hxIndicator":             convertArgs(hx.Indicator),
+		"hxParamsAll":             convertArgs(hx.ParamsAll),
+		"hxParamsNone":            convertArgs(hx.ParamsNone),
+		"hxParams":                convertArgs(hx.Params),
+		"hxParamsNot":             convertArgs(hx.ParamsNot),
+		"hxPatch":                 convertArgs(hx.Patch),
+		"hxPreserve":              convertArgs(hx.Preserve),
+		"hxPrompt":                convertArgs(hx.Prompt),
+		"hxPut":                   convertArgs(hx.Put),
+		"hxReplaceURL":            convertArgs(hx.ReplaceURL),
+		"hxReplaceURLWith":        convertArgs(hx.ReplaceURLWith),
+		"hxRequest":               convertArgs(hx.Request),
+		"hxRequestJS":             convertArgs(hx.RequestJS),
+		"hxSync":                  convertArgs(hx.Sync),
+		"hxSyncStrategy":          convertArgs(hx.SyncStrategy),
+		"hxValidate":              convertArgs(hx.Validate),
+		"hxUnset":                 convertArgs(hx.Unset),
+		"hxAttr":                  convertArgs(hx.Attr),
+	}
+}
+
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+
+// convertArgs wraps a function to take any arguments, and convert each one to the function's parameter type.
+// html/template only passes values of the exact parameter type, so without this a string field couldn't be passed as a [TargetSelector].
+func convertArgs(fn any) any {
+	f := reflect.ValueOf(fn)
+	t := f.Type()
+
+	in := make([]reflect.Type, t.NumIn())
+	for i := range in {
+		in[i] = anyType
+	}
+	if t.IsVariadic() {
+		in[len(in)-1] = reflect.SliceOf(anyType)
+	}
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+
+	return reflect.MakeFunc(reflect.FuncOf(in, out, t.IsVariadic()), func(args []reflect.Value) []reflect.Value {
+		if t.IsVariadic() {
+			rest := args[len(args)-1]
+			args = args[:len(args)-1]
+			for i := 0; i < rest.Len(); i++ {
+				args = append(args, rest.Index(i))
+			}
+		}
+
+		converted := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			param := t.In(min(i, t.NumIn()-1))
+			if t.IsVariadic() && i >= t.NumIn()-1 {
+				param = param.Elem()
+			}
+			converted[i] = convertArg(arg, param)
+		}
+		return f.Call(converted)
+	}).Interface()
+}
+
+// convertArg converts an argument to a parameter type, if it's assignable or has the same kind, like a string to a [TargetSelector].
+// html/template recovers the panic for any other argument, and returns it as an error.
+func convertArg(arg reflect.Value, param reflect.Type) reflect.Value {
+	if arg.Kind() == reflect.Interface {
+		if arg.IsNil() {
+			return reflect.Zero(param)
+		}
+		arg = arg.Elem()
+	}
+	switch {
+	case arg.Type().AssignableTo(param):
+		return arg
+	case arg.Kind() == param.Kind() && arg.Type().ConvertibleTo(param):
+		return arg.Convert(param)
+	}
+	panic(fmt.Errorf("wrong type for value; expected %s; got %s", param, arg.Type()))
+}
@@ -0,0 +1,130 @@
+package htmx_test
+
+import (
+	"html/template"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+)
+
+func ExampleFuncMap() {
+	tmpl := template.Must(template.New("row").Funcs(htmx.FuncMap()).Parse(
+		`<tr {{ hxTarget "this" }} {{ hxSwapExtended ((swap "outerHTML").Transition) }}>` +
+			`<button {{ hxDelete .URL }} {{ hxConfirm "It'll be gone." }}>Delete</button>` +
+			`</tr>`,
+	))
+
+	_ = tmpl.Execute(os.Stdout, map[string]string{"URL": "/contacts/1?a=1&b=2"})
+	// Output: <tr hx-target="this" hx-swap="outerHTML transition:true"><button hx-delete="/contacts/1?a=1&amp;b=2" hx-confirm="It&#39;ll be gone.">Delete</button></tr>
+}
+
+func TestFuncMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		data     any
+		want     string
+	}{
+		{
+			name:     "boolean attribute",
+			template: `<div {{ hxPreserve }}></div>`,
+			want:     `<div hx-preserve></div>`,
+		},
+		{
+			name:     "trigger builders",
+			template: `<input {{ hxTriggerExtended ((trigger "keyup").Changed.Delay (duration "500ms")) (trigger "search") (every (duration "1s")) }}>`,
+			want:     `<input hx-trigger="keyup changed delay:500ms, search, every 1s">`,
+		},
+		{
+			name:     "vals from data",
+			template: `<div {{ hxVals . }}></div>`,
+			data:     map[string]string{"q": `"quoted" & <b>`},
+			want:     `<div hx-vals="{&#34;q&#34;:&#34;\&#34;quoted\&#34; \u0026 \u003cb\u003e&#34;}"></div>`,
+		},
+		{
+			name:     "variadic attributes",
+			template: `<div {{ hxParams "a" "b" }}></div>`,
+			want:     `<div hx-params="a,b"></div>`,
+		},
+		{
+			name:     "config",
+			template: `<meta name="htmx-config" {{ hxConfig (hxconfig.Timeout (duration "1s")) }}>`,
+			want:     `<meta name="htmx-config" content="{&#34;timeout&#34;:1000}">`,
+		},
+		{
+			name:     "selector and strategy from data",
+			template: `<div {{ hxTarget .Selector }} {{ hxSwap .Strategy }} {{ hxInclude .Selector }}></div>`,
+			data:     struct{ Selector, Strategy string }{Selector: "#result", Strategy: "outerHTML"},
+			want:     `<div hx-target="#result" hx-swap="outerHTML" hx-include="#result"></div>`,
+		},
+		{
+			name:     "variadic and builder arguments from data",
+			template: `<div {{ hxSelectOOB .Selector "#b" }} {{ hxSwapExtended (swap .Strategy) }} {{ hxTriggerExtended (trigger .Event) }}></div>`,
+			data:     struct{ Selector, Strategy, Event string }{Selector: "#a", Strategy: "innerHTML", Event: "click"},
+			want:     `<div hx-select-oob="#a,#b" hx-swap="innerHTML" hx-trigger="click"></div>`,
+		},
+		{
+			name:     "on",
+			template: `<form {{ hxOn "htmx:after-request" "this.reset()" }}></form>`,
+			want:     `<form hx-on:htmx:after-request="this.reset()"></form>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New(tt.name).Funcs(htmx.FuncMap()).Parse(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, tt.data); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %s, want %s", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFuncMap_wrongType(t *testing.T) {
+	tmpl := template.Must(template.New("wrong").Funcs(htmx.FuncMap()).Parse(`<div {{ hxTarget .Selector }}></div>`))
+	err := tmpl.Execute(io.Discard, map[string]int{"Selector": 1})
+	if err == nil || !strings.Contains(err.Error(), "wrong type for value; expected target.Selector; got int") {
+		t.Errorf("got error %v, want a wrong type error", err)
+	}
+}
+
+func TestFuncMap_complete(t *testing.T) {
+	funcs := htmx.FuncMap(htmx.WithVersion(htmx.V2))
+	hx := htmx.NewStringAttrs()
+	methods := reflect.TypeOf(&hx)
+	for i := 0; i < methods.NumMethod(); i++ {
+		method := methods.Method(i)
+		if method.Type.NumOut() != 1 || method.Type.Out(0).Kind() != reflect.String || method.Name == "Version" || method.Name == "Reject" {
+			continue
+		}
+		if _, ok := funcs["hx"+method.Name]; !ok {
+			t.Errorf("missing hx%s", method.Name)
+		}
+	}
+	if _, ok := funcs["hxReject"]; ok {
+		t.Error("got hxReject, want it left out")
+	}
+}
+
+func TestNewStringAttrs_escaping(t *testing.T) {
+	hx := htmx.NewStringAttrs()
+	if got, want := hx.Confirm("it's gone & done"), `hx-confirm='it&#39;s gone &amp; done'`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := hx.Vals(map[string]string{"name": "O'Brien"}), `hx-vals='{"name":"O&#39;Brien"}'`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := hx.Attr("hx-history-cache-size", 10), `hx-history-cache-size='10'`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -14,10 +14,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/will-wow/typed-htmx-go/htmx/internal/target"
 	"github.com/will-wow/typed-htmx-go/htmx/internal/util"
 	"github.com/will-wow/typed-htmx-go/htmx/on"
 	"github.com/will-wow/typed-htmx-go/htmx/swap"
 	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+	"github.com/will-wow/typed-htmx-go/htmx/version"
 )
 
 type NewAttr[T any] func(key Attribute, value any) T
@@ -25,11 +27,21 @@ type NewAttr[T any] func(key Attribute, value any) T
 // An HX constructs HTMX attributes.
 type HX[T any] struct {
 	attr NewAttr[T]
+	opts options
 }
 
-func NewHX[T any](attr NewAttr[T]) HX[T] {
+// NewHX returns an HX that builds attributes with the attr function, configured by any options.
+func NewHX[T any](attr NewAttr[T], opts ...Option) HX[T] {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.dataPrefix {
+		attr = withDataPrefix(attr)
+	}
 	return HX[T]{
 		attr: attr,
+		opts: o,
 	}
 }
 
@@ -160,12 +172,16 @@ func (hx *HX[T]) Post(url string, a ...any) T {
 // # Notes
 //
 //   - hx-on is not inherited, however due to event bubbling, hx-on attributes on parent elements will typically be triggered by events on child elements.
+//   - With [V2], htmx events use the shorter hx-on::event form, like `hx-on::after-request`.
 //
 // HTMX Attribute: [hx-on]
 //
 // [hx-on]: https://htmx.org/attributes/hx-on/
 // [HTMX events]: https://htmx.org/docs/#events
 func (hx *HX[T]) On(event on.Event, action string) T {
+	if name, ok := strings.CutPrefix(event, "htmx:"); ok && hx.opts.version >= V2 {
+		return hx.attr(Attribute(fmt.Sprintf("hx-on::%s", name)), action)
+	}
 	return hx.attr(Attribute(fmt.Sprintf("hx-on:%s", event)), action)
 }
 
@@ -249,6 +265,9 @@ func (hx *HX[T]) PushURLPath(url string, a ...any) T {
 //
 // [hx-select]: https://htmx.org/attributes/hx-select/
 func (hx *HX[T]) Select(selector StandardCSSSelector) T {
+	if err := selectorError(Select, string(selector)); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(Select, string(selector))
 }
 
@@ -295,14 +314,31 @@ func (hx *HX[T]) Select(selector StandardCSSSelector) T {
 //
 // [hx-select-oob]: https://htmx.org/attributes/hx-select-oob/
 func (hx *HX[T]) SelectOOB(selectors ...StandardCSSSelector) T {
+	if len(selectors) == 0 {
+		return hx.Reject(selectorError(SelectOOB, ""))
+	}
+	for _, selector := range selectors {
+		if err := selectorError(SelectOOB, string(selector)); err != nil {
+			return hx.Reject(err)
+		}
+	}
 	return hx.attr(SelectOOB, util.JoinStringLikes(selectors, ","))
 }
 
+// A SelectOOBStrategy is a selector for [HX.SelectOOBWithStrategy], with an optional swap strategy.
 type SelectOOBStrategy struct {
 	Selector StandardCSSSelector
 	Strategy swap.Strategy
 }
 
+// String returns the hx-select-oob string for the selector and strategy, used internally by [HX.SelectOOBWithStrategy].
+func (s SelectOOBStrategy) String() string {
+	if s.Strategy == "" {
+		return string(s.Selector)
+	}
+	return fmt.Sprintf("%s:%s", s.Selector, s.Strategy)
+}
+
 // SelectOOBWithStrategy allows you to select content from a response to be swapped in via an out-of-band swap, with an optional strategy for each selector.
 //
 // The value of this attribute is comma separated list of elements to be swapped out of band. This attribute is almost always paired with hx-select.
@@ -333,13 +369,18 @@ type SelectOOBStrategy struct {
 //
 // [hx-select-oob]: https://htmx.org/attributes/hx-select-oob
 func (hx *HX[T]) SelectOOBWithStrategy(selectors ...SelectOOBStrategy) T {
+	if len(selectors) == 0 {
+		return hx.Reject(selectorError(SelectOOB, ""))
+	}
 	values := make([]string, len(selectors))
 	for i, s := range selectors {
-		if s.Strategy == "" {
-			values[i] = string(s.Selector)
-		} else {
-			values[i] = fmt.Sprintf("%s:%s", s.Selector, s.Strategy)
+		if err := hx.strategyError(s.Strategy); err != nil {
+			return hx.Reject(err)
+		}
+		if err := selectorError(SelectOOB, string(s.Selector)); err != nil {
+			return hx.Reject(err)
 		}
+		values[i] = s.String()
 	}
 
 	return hx.attr(SelectOOB, strings.Join(values, ","))
@@ -361,6 +402,9 @@ func (hx *HX[T]) SelectOOBWithStrategy(selectors ...SelectOOBStrategy) T {
 //
 // [hx-swap]: https://htmx.org/attributes/hx-swap
 func (hx *HX[T]) Swap(strategy swap.Strategy) T {
+	if err := hx.strategyError(strategy); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(Swap, string(strategy))
 }
 
@@ -382,8 +426,13 @@ func (hx *HX[T]) Swap(strategy swap.Strategy) T {
 // HTMX Attribute: [hx-swap]
 //
 // [hx-swap]: https://htmx.org/attributes/hx-swap
-func (hx *HX[T]) SwapExtended(swap *swap.Builder) T {
-	return hx.attr(Swap, swap.String())
+func (hx *HX[T]) SwapExtended(builder *swap.Builder) T {
+	value := builder.String()
+	strategy, _, _ := strings.Cut(value, " ")
+	if err := hx.strategyError(swap.Strategy(strategy)); err != nil {
+		return hx.Reject(err)
+	}
+	return hx.attr(Swap, value)
 }
 
 // SwapOOP allows you to specify that some content in a response should be swapped into the DOM somewhere other than the target by ID, that is “Out of Band”. This allows you to piggy back updates to other element updates on a response.
@@ -435,6 +484,9 @@ func (hx *HX[T]) SwapOOB() T {
 //
 // [hx-swap-oob]: https://htmx.org/attributes/hx-swap-oob
 func (hx *HX[T]) SwapOOBWithStrategy(strategy swap.Strategy) T {
+	if err := hx.strategyError(strategy); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(SwapOOB, string(strategy))
 }
 
@@ -461,11 +513,33 @@ func (hx *HX[T]) SwapOOBWithStrategy(strategy swap.Strategy) T {
 //
 // [hx-swap-oob]: https://htmx.org/attributes/hx-swap-oob
 func (hx *HX[T]) SwapOOBSelector(strategy swap.Strategy, cssSelector string) T {
+	if err := hx.strategyError(strategy); err != nil {
+		return hx.Reject(err)
+	}
+	if err := selectorError(SwapOOB, cssSelector); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(SwapOOB, fmt.Sprintf("%s:%s", strategy, cssSelector))
 }
 
+// strategyError checks that a swap strategy exists in the chosen version of htmx.
+func (hx *HX[T]) strategyError(strategy swap.Strategy) error {
+	if strategy == swap.TextContent && hx.opts.version < V2 {
+		return version.Unsupported("the textContent swap strategy", hx.opts.version)
+	}
+	return nil
+}
+
+// selectorError returns an error wrapping [ErrInvalid] for an empty selector, which htmx can't use.
+func selectorError(attribute Attribute, selector string) error {
+	if strings.TrimSpace(selector) == "" {
+		return fmt.Errorf("%w: %s: empty selector", ErrInvalid, attribute)
+	}
+	return nil
+}
+
 // A TargetSelector is a CSS selector, or a non-standard selector for the [HX.Target()] attribute.
-type TargetSelector string
+type TargetSelector = target.Selector
 
 const (
 	TargetThis     TargetSelector = "this"     // indicates that the element that the hx-target attribute is on is the target.
@@ -503,6 +577,9 @@ var TargetRelative = util.MakeRelativeSelector[RelativeModifier, TargetSelector]
 //
 // [hx-target]: https://htmx.org/attributes/hx-target
 func (hx *HX[T]) Target(extendedSelector TargetSelector) T {
+	if err := selectorError(Target, string(extendedSelector)); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(Target, string(extendedSelector))
 }
 
@@ -532,6 +609,9 @@ func (hx *HX[T]) Trigger(event trigger.TriggerEvent) T {
 func (hx *HX[T]) TriggerExtended(triggers ...trigger.Trigger) T {
 	values := make([]string, len(triggers))
 	for i, t := range triggers {
+		if err := t.Validate(); err != nil {
+			return hx.Reject(err)
+		}
 		values[i] = t.String()
 	}
 
@@ -543,6 +623,7 @@ func (hx *HX[T]) TriggerExtended(triggers ...trigger.Trigger) T {
 // The value of this attribute is a list of name-expression values in JSON (JavaScript Object Notation) format, marshaled from a struct or map.
 //
 // By default, the value of hx-vals must be valid JSON. It is not dynamically computed.
+// Values that can't be marshaled render nothing, and the error is reported to [OnError].
 //
 // # Notes
 //
@@ -556,8 +637,7 @@ func (hx *HX[T]) TriggerExtended(triggers ...trigger.Trigger) T {
 func (hx *HX[T]) Vals(vals any) T {
 	json, err := json.Marshal(vals)
 	if err != nil {
-		// Silently ignore the value if there is an error, because there's not a good way to report an error when constructing templ attributes.
-		return hx.attr(Vals, "{}")
+		return hx.Reject(fmt.Errorf("%w: %s: %w", ErrInvalid, Vals, err))
 	}
 	return hx.attr(Vals, string(json))
 }
@@ -694,6 +774,9 @@ var DisabledEltRelative = util.MakeRelativeSelector[DisabledEltModifier, Disable
 //
 // [hx-disabled-elt]: https://htmx.org/attributes/hx-disabled-elt
 func (hx *HX[T]) DisabledElt(extendedSelector DisabledEltSelector) T {
+	if err := selectorError(DisabledElt, string(extendedSelector)); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(DisabledElt, string(extendedSelector))
 }
 
@@ -773,6 +856,51 @@ func (hx *HX[T]) DisinheritAll() T {
 	return hx.attr(Disinherit, "*")
 }
 
+// Inherit allows you to enable automatic attribute inheritance for some attributes, when it has been disabled globally with the htmx 2 disableInheritance config.
+//
+// An example scenario is to allow you to place an hx-target on the body element of a page, but only have it apply to the elements that opt in.
+//
+//	<div
+//		{ hx.Target("#tab-container")... }
+//		{ hx.Inherit(htmx.Target)... }
+//	>
+//		<a { hx.Get("/tab1")... }>Tab 1</a> <!-- targets #tab-container -->
+//	</div>
+//
+// Notes
+//
+//   - Requires htmx 2.x, see [WithVersion].
+//   - Read more about [Attribute Inheritance]
+//
+// HTMX Attribute: [hx-inherit]
+//
+// [hx-inherit]: https://htmx.org/attributes/hx-inherit/
+// [Attribute Inheritance]: https://htmx.org/docs/#inheritance
+func (hx *HX[T]) Inherit(attr ...Attribute) T {
+	// Convert to strings for joining.
+	attrStrings := make([]string, len(attr))
+	for i, a := range attr {
+		attrStrings[i] = string(a)
+	}
+
+	return hx.Attr(Inherit, strings.Join(attrStrings, " "))
+}
+
+// InheritAll allows you to enable automatic attribute inheritance for all attributes, when it has been disabled globally with the htmx 2 disableInheritance config.
+//
+// Notes
+//
+//   - Requires htmx 2.x, see [WithVersion].
+//   - Read more about [Attribute Inheritance]
+//
+// HTMX Attribute: [hx-inherit]
+//
+// [hx-inherit]: https://htmx.org/attributes/hx-inherit/
+// [Attribute Inheritance]: https://htmx.org/docs/#inheritance
+func (hx *HX[T]) InheritAll() T {
+	return hx.Attr(Inherit, "*")
+}
+
 // An EncodingContentType is a valid encoding override for an [HX.Encoding()].
 type EncodingContentType string
 
@@ -842,6 +970,7 @@ func (hx *HX[T]) ExtIgnore(ext string) T {
 // Headers allows you to add to the headers that will be submitted with an AJAX request.
 //
 // The value of this attribute is a list of name-expression values in JSON (JavaScript Object Notation) format.
+// Values that can't be marshaled render nothing, and the error is reported to [OnError].
 //
 // For values computed at runtime, see [HX.HeadersJS()].
 //
@@ -856,8 +985,7 @@ func (hx *HX[T]) ExtIgnore(ext string) T {
 func (hx *HX[T]) Headers(headers any) T {
 	json, err := json.Marshal(headers)
 	if err != nil {
-		// Silently ignore the value if there is an error, because there's not a good way to report an error when constructing attributes.
-		return hx.attr(Headers, "{}")
+		return hx.Reject(fmt.Errorf("%w: %s: %w", ErrInvalid, Headers, err))
 	}
 	return hx.attr(Headers, string(json))
 }
@@ -943,6 +1071,9 @@ var IncludeRelative = util.MakeRelativeSelector[RelativeModifier, IncludeSelecto
 //
 // [hx-include]: https://htmx.org/attributes/hx-include/
 func (hx *HX[T]) Include(extendedSelector IncludeSelector) T {
+	if err := selectorError(Include, string(extendedSelector)); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(Include, string(extendedSelector))
 }
 
@@ -963,6 +1094,9 @@ var IndicatorRelative = util.MakeRelativeSelector[IndicatorModifier, IndicatorSe
 //
 // [hx-indicator]: https://htmx.org/attributes/hx-indicator/
 func (hx *HX[T]) Indicator(extendedSelector IndicatorSelector) T {
+	if err := selectorError(Indicator, string(extendedSelector)); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(Indicator, string(extendedSelector))
 }
 
@@ -1280,6 +1414,9 @@ var SyncRelative = util.MakeRelativeSelector[RelativeModifier, SyncSelector]()
 //
 // [hx-sync]: https://htmx.org/attributes/hx-sync/
 func (hx *HX[T]) Sync(extendedSelector SyncSelector) T {
+	if err := selectorError(Sync, string(extendedSelector)); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(Sync, string(extendedSelector))
 }
 
@@ -1309,6 +1446,9 @@ const (
 //
 // [hx-sync]: https://htmx.org/attributes/hx-sync/
 func (hx *HX[T]) SyncStrategy(extendedSelector SyncSelector, strategy SyncStrategy) T {
+	if err := selectorError(Sync, string(extendedSelector)); err != nil {
+		return hx.Reject(err)
+	}
 	return hx.attr(Sync, fmt.Sprintf("%s:%s", extendedSelector, strategy))
 }
 
@@ -1338,7 +1478,12 @@ type ExtAttribute struct {
 	Value     any
 }
 
+// Attr sets any attribute, for attributes and extensions that don't have a typed function.
+// Known htmx attributes that don't exist in the chosen [Version] are rejected.
 func (hx *HX[T]) Attr(attribute Attribute, value any) T {
+	if added, ok := addedIn[attribute]; ok && hx.opts.version < added {
+		return hx.Reject(version.Unsupported(string(attribute), hx.opts.version))
+	}
 	return hx.attr(attribute, value)
 }
 
@@ -1378,8 +1523,14 @@ const (
 	Request     Attribute = "hx-request"
 	Sync        Attribute = "hx-sync"
 	Validate    Attribute = "hx-validate"
+	Inherit     Attribute = "hx-inherit" // Requires htmx 2.x.
 )
 
+// addedIn maps the attributes added after htmx 1.x to the version that added them.
+var addedIn = map[Attribute]Version{
+	Inherit: V2,
+}
+
 // A RelativeModifier is a relative modifier to a CSS selector. This is used for "extended selectors".
 // Some attributes only support a subset of these, but any Relative function that takes this type supports the full set..
 type RelativeModifier string
@@ -152,8 +152,13 @@ func ExampleHX_Vals() {
 }
 
 func ExampleHX_Vals_error() {
-	fmt.Println(hx.Vals(func() {}))
-	// Output: hx-vals='{}'
+	hx := htmx.NewStringAttrs(htmx.OnError(func(err error) {
+		fmt.Println(err)
+	}))
+	fmt.Printf("%q\n", hx.Vals(func() {}))
+	// Output:
+	// htmx: invalid attribute value: hx-vals: json: unsupported type: func()
+	// ""
 }
 
 func ExampleHX_Vals_invalid() {
@@ -240,8 +245,13 @@ func ExampleHX_Headers() {
 }
 
 func ExampleHX_Headers_error() {
-	fmt.Println(hx.Headers(func() {}))
-	// Output: hx-headers='{}'
+	hx := htmx.NewStringAttrs(htmx.OnError(func(err error) {
+		fmt.Println(err)
+	}))
+	fmt.Printf("%q\n", hx.Headers(func() {}))
+	// Output:
+	// htmx: invalid attribute value: hx-headers: json: unsupported type: func()
+	// ""
 }
 
 func ExampleHX_HeadersJS() {
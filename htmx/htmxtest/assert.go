@@ -0,0 +1,131 @@
+package htmxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/hxres"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+)
+
+// Triggers decodes a trigger header, like [hxres.Trigger], into a map of event names to their JSON details.
+// Events sent without details, as a comma-separated list, have a nil detail.
+func Triggers(h http.Header, header hxres.Header) (map[trigger.TriggerEvent]json.RawMessage, error) {
+	value := strings.TrimSpace(h.Get(string(header)))
+	events := map[trigger.TriggerEvent]json.RawMessage{}
+	if value == "" {
+		return events, nil
+	}
+
+	if !strings.HasPrefix(value, "{") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				events[trigger.TriggerEvent(name)] = nil
+			}
+		}
+		return events, nil
+	}
+
+	var details map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &details); err != nil {
+		return nil, fmt.Errorf("htmxtest: invalid %s: %w", header, err)
+	}
+	for name, detail := range details {
+		if bytes.Equal(detail, []byte("null")) {
+			detail = nil
+		}
+		events[trigger.TriggerEvent(name)] = detail
+	}
+	return events, nil
+}
+
+// AssertTrigger checks that the response's HX-Trigger header includes every event.
+func AssertTrigger(t testing.TB, w *httptest.ResponseRecorder, events ...trigger.TriggerEvent) {
+	t.Helper()
+
+	triggers, err := Triggers(w.Header(), hxres.Trigger)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, event := range events {
+		if _, ok := triggers[event]; !ok {
+			t.Errorf("expected %s to include %q, got %q", hxres.Trigger, event, w.Header().Get(string(hxres.Trigger)))
+		}
+	}
+}
+
+// AssertTriggerDetail checks that the response's HX-Trigger header includes the typed event, with a detail equal to want.
+func AssertTriggerDetail[T any](t testing.TB, w *httptest.ResponseRecorder, event hxres.Event[T], want T) {
+	t.Helper()
+
+	triggers, err := Triggers(w.Header(), hxres.Trigger)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	detail, ok := triggers[event.Name()]
+	if !ok || detail == nil {
+		t.Errorf("expected %s to include %q with a detail, got %q", hxres.Trigger, event.Name(), w.Header().Get(string(hxres.Trigger)))
+		return
+	}
+
+	var got T
+	if err := json.Unmarshal(detail, &got); err != nil {
+		t.Errorf("could not decode %q detail %s: %v", event.Name(), detail, err)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q detail %+v, got %+v", event.Name(), want, got)
+	}
+}
+
+// AssertHeader checks the value of an htmx response header.
+func AssertHeader(t testing.TB, w *httptest.ResponseRecorder, header hxres.Header, want string) {
+	t.Helper()
+
+	if got := w.Header().Get(string(header)); got != want {
+		t.Errorf("expected %s %q, got %q", header, want, got)
+	}
+}
+
+// AssertReswap checks that the response's HX-Reswap header matches the swap builder.
+func AssertReswap(t testing.TB, w *httptest.ResponseRecorder, want *swap.Builder) {
+	t.Helper()
+	AssertHeader(t, w, hxres.Reswap, want.String())
+}
+
+// AssertRetarget checks that the response's HX-Retarget header matches the selector.
+func AssertRetarget(t testing.TB, w *httptest.ResponseRecorder, want htmx.TargetSelector) {
+	t.Helper()
+	AssertHeader(t, w, hxres.Retarget, string(want))
+}
+
+// AssertStopPolling checks that the response uses the [hxres.StatusStopPolling] status, to stop a polling element.
+func AssertStopPolling(t testing.TB, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	if w.Code != hxres.StatusStopPolling {
+		t.Errorf("expected status %d, got %d", hxres.StatusStopPolling, w.Code)
+	}
+}
+
+// AssertOOB checks that the response body includes an out-of-band fragment for each id.
+func AssertOOB(t testing.TB, w *httptest.ResponseRecorder, ids ...string) {
+	t.Helper()
+
+	fragments := OOB(w.Body.String())
+	for _, id := range ids {
+		if _, ok := fragments[id]; !ok {
+			t.Errorf("expected an out-of-band fragment with id %q, got %v", id, fragments)
+		}
+	}
+}
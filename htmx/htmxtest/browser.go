@@ -0,0 +1,627 @@
+package htmxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/hxreq"
+	"github.com/will-wow/typed-htmx-go/htmx/hxres"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+// ErrNotFound is returned when no element matches a selector.
+var ErrNotFound = errors.New("htmxtest: no element matches the selector")
+
+// ErrNoRequest is returned when an element is clicked or submitted, but would not make a request.
+var ErrNoRequest = errors.New("htmxtest: element does not make a request")
+
+// ErrTooManyRedirects is returned when loading a page is redirected more than 10 times, like a redirect loop.
+var ErrTooManyRedirects = errors.New("htmxtest: stopped after 10 redirects")
+
+// ErrUnsupported is returned for htmx features the simulator can't run, like javascript hx-vals.
+var ErrUnsupported = errors.New("htmxtest: not supported by the simulator")
+
+// A Browser simulates how htmx handles interactions with a page, without running any JavaScript.
+// It sends requests to an [http.Handler], and applies the responses to an in-memory DOM, so whole flows can be tested end-to-end.
+//
+// The simulator reads hx-get, hx-post, hx-put, hx-patch, hx-delete, hx-target, hx-swap, hx-select, hx-include, hx-vals, hx-push-url and hx-boost, including inherited values. It applies out-of-band swaps, and the HX-Retarget, HX-Reswap, HX-Reselect, HX-Redirect, HX-Location, HX-Refresh, HX-Push-Url and HX-Replace-Url response headers.
+// Like htmx, an HX-Location header makes a GET request that is swapped into the header's target, or the body, without loading a new page.
+// Triggers are not simulated: [Browser.Click], [Browser.Submit] and [Browser.Trigger] make the element's request directly. Swap modifiers, like timing and scrolling, are ignored.
+//
+//	b := htmxtest.NewBrowser(handler, htmx.V2)
+//	_ = b.Open("/contacts/1")
+//	_ = b.Click("button")
+//	_ = b.Fill("input[name=email]", "joe@example.com")
+//	_ = b.Submit("form")
+//
+//	text, _ := b.Text("#contact")
+type Browser struct {
+	handler    http.Handler
+	doc        *html.Node
+	location   *url.URL
+	swapStatus map[int]bool
+	response   *httptest.ResponseRecorder
+	version    htmx.Version
+}
+
+// NewBrowser creates a browser for pages served by the handler, that behaves like the given version of htmx.
+// With [htmx.V1], DELETE requests send their values in the form body, and with [htmx.V2], in the query string.
+//
+// Like htmx, it only swaps in responses with a 2xx status, other than 204 No Content. Use [Browser.SwapStatus] to swap other statuses.
+func NewBrowser(handler http.Handler, v htmx.Version) *Browser {
+	doc, _ := html.Parse(strings.NewReader(""))
+	return &Browser{
+		handler:    handler,
+		doc:        doc,
+		location:   &url.URL{Scheme: "http", Host: "example.com", Path: "/"},
+		swapStatus: map[int]bool{},
+		response:   nil,
+		version:    v,
+	}
+}
+
+// SwapStatus swaps in responses with the status codes, like a page with an htmx:beforeSwap handler that sets shouldSwap.
+func (b *Browser) SwapStatus(codes ...int) *Browser {
+	for _, code := range codes {
+		b.swapStatus[code] = true
+	}
+	return b
+}
+
+// Open loads a full page, like typing the URL into the address bar.
+func (b *Browser) Open(path string) error {
+	return b.navigate(http.MethodGet, path, nil)
+}
+
+// URL returns the current URL of the page, including any URL pushed into history by htmx.
+func (b *Browser) URL() string {
+	return b.location.String()
+}
+
+// Response returns the response to the last request, to be checked with the htmxtest assertions.
+func (b *Browser) Response() *httptest.ResponseRecorder {
+	return b.response
+}
+
+// Document returns the root node of the current page.
+func (b *Browser) Document() *html.Node {
+	return b.doc
+}
+
+// HTML renders the current page.
+func (b *Browser) HTML() string {
+	var buf bytes.Buffer
+	_ = html.Render(&buf, b.doc)
+	return buf.String()
+}
+
+// Find returns the first element matching a CSS selector.
+func (b *Browser) Find(selector string) (*html.Node, error) {
+	nodes, err := queryAll(b.doc, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, selector)
+	}
+	return nodes[0], nil
+}
+
+// OuterHTML renders the first element matching a CSS selector.
+func (b *Browser) OuterHTML(selector string) (string, error) {
+	n, err := b.Find(selector)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = html.Render(&buf, n)
+	return buf.String(), err
+}
+
+// Text returns the text content of the first element matching a CSS selector, with whitespace collapsed.
+func (b *Browser) Text(selector string) (string, error) {
+	n, err := b.Find(selector)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(textContent(n)), " "), nil
+}
+
+// Fill sets the value of an input, textarea, or select.
+func (b *Browser) Fill(selector string, value string) error {
+	n, err := b.Find(selector)
+	if err != nil {
+		return err
+	}
+
+	switch n.DataAtom {
+	case atom.Textarea:
+		removeChildren(n)
+		n.AppendChild(&html.Node{Type: html.TextNode, Data: value})
+	case atom.Select:
+		for _, option := range options(n) {
+			if optionValue(option) == value {
+				setAttr(option, "selected", "")
+			} else if !hasAttr(n, "multiple") {
+				removeAttr(option, "selected")
+			}
+		}
+	default:
+		setAttr(n, "value", value)
+	}
+	return nil
+}
+
+// Check checks or unchecks a checkbox or radio input. Checking a radio input unchecks the others in its group.
+func (b *Browser) Check(selector string, checked bool) error {
+	n, err := b.Find(selector)
+	if err != nil {
+		return err
+	}
+
+	if !checked {
+		removeAttr(n, "checked")
+		return nil
+	}
+
+	if getAttr(n, "type") == "radio" {
+		name := getAttr(n, "name")
+		for _, other := range descendants(b.doc) {
+			if other.DataAtom == atom.Input && getAttr(other, "type") == "radio" && getAttr(other, "name") == name {
+				removeAttr(other, "checked")
+			}
+		}
+	}
+	setAttr(n, "checked", "")
+	return nil
+}
+
+// Click clicks the first element matching a CSS selector.
+//
+// Elements with an hx-get, hx-post, etc. make their request. Submit buttons submit their form, and links follow their href, using hx-boost if it's enabled.
+func (b *Browser) Click(selector string) error {
+	n, err := b.Find(selector)
+	if err != nil {
+		return err
+	}
+
+	if _, _, ok := requestAttr(n); ok {
+		return b.issue(n, nil)
+	}
+
+	if isSubmitButton(n) {
+		if form := closest(n, func(n *html.Node) bool { return n.DataAtom == atom.Form }); form != nil {
+			return b.submit(form, n)
+		}
+	}
+
+	if n.DataAtom == atom.A && hasAttr(n, "href") {
+		href := getAttr(n, "href")
+		if isBoosted(n) {
+			return b.boost(n, http.MethodGet, href, nil)
+		}
+		return b.navigate(http.MethodGet, href, nil)
+	}
+
+	return fmt.Errorf("%w: %s", ErrNoRequest, selector)
+}
+
+// Submit submits the first form matching a CSS selector, using its hx-post, etc., or its action if it's boosted or a plain form.
+func (b *Browser) Submit(selector string) error {
+	n, err := b.Find(selector)
+	if err != nil {
+		return err
+	}
+	return b.submit(n, nil)
+}
+
+// Trigger makes the htmx request of the first element matching a CSS selector, whatever its hx-trigger is.
+func (b *Browser) Trigger(selector string) error {
+	n, err := b.Find(selector)
+	if err != nil {
+		return err
+	}
+	if _, _, ok := requestAttr(n); !ok {
+		return fmt.Errorf("%w: %s", ErrNoRequest, selector)
+	}
+	return b.issue(n, nil)
+}
+
+// submit submits a form, optionally with the button that submitted it.
+func (b *Browser) submit(form *html.Node, submitter *html.Node) error {
+	if _, _, ok := requestAttr(form); ok {
+		return b.issue(form, submitter)
+	}
+
+	method := strings.ToUpper(getAttr(form, "method"))
+	if method == "" {
+		method = http.MethodGet
+	}
+	action := getAttr(form, "action")
+
+	values, err := b.values(form, strings.ToLower(method), submitter)
+	if err != nil {
+		return err
+	}
+
+	if isBoosted(form) {
+		return b.boost(form, method, action, values)
+	}
+	return b.navigate(method, action, values)
+}
+
+// maxRedirects is the number of redirects followed for a page load, the same as the net/http client.
+const maxRedirects = 10
+
+// navigate loads a full page without htmx, following redirects.
+func (b *Browser) navigate(method string, path string, values url.Values) error {
+	req, err := b.newRequest(method, path, values)
+	if err != nil {
+		return err
+	}
+
+	w := b.serve(req)
+	for redirects := 0; w.Code >= 300 && w.Code < 400 && w.Header().Get("Location") != ""; redirects++ {
+		if redirects == maxRedirects {
+			return fmt.Errorf("%w: %s", ErrTooManyRedirects, req.URL)
+		}
+		b.location = req.URL
+		if req, err = b.newRequest(http.MethodGet, w.Header().Get("Location"), nil); err != nil {
+			return err
+		}
+		w = b.serve(req)
+	}
+
+	doc, err := html.Parse(w.Body)
+	if err != nil {
+		return err
+	}
+	b.doc = doc
+	b.location = req.URL
+	return nil
+}
+
+// exchange describes an htmx request and how to swap its response.
+type exchange struct {
+	elt      *html.Node
+	method   string
+	path     string
+	values   url.Values
+	target   *html.Node
+	strategy swap.Strategy
+	selector string
+	pushURL  string
+	boosted  bool
+	headers  map[string]string
+}
+
+// issue makes the htmx request for an element.
+func (b *Browser) issue(elt *html.Node, submitter *html.Node) error {
+	method, path, _ := requestAttr(elt)
+
+	target, err := b.target(elt)
+	if err != nil {
+		return err
+	}
+
+	values, err := b.values(elt, strings.ToLower(method), submitter)
+	if err != nil {
+		return err
+	}
+
+	pushURL, _ := inheritedAttr(elt, string(htmx.PushURL))
+
+	return b.exchange(exchange{
+		elt:      elt,
+		method:   method,
+		path:     path,
+		values:   values,
+		target:   target,
+		strategy: b.strategy(elt),
+		selector: inheritedOrEmpty(elt, string(htmx.Select)),
+		pushURL:  pushURL,
+		boosted:  false,
+		headers:  nil,
+	})
+}
+
+// boost makes a boosted request for a link or form, which swaps the body.
+func (b *Browser) boost(elt *html.Node, method string, path string, values url.Values) error {
+	target := b.body()
+	if value, ok := inheritedAttr(elt, string(htmx.Target)); ok {
+		resolved, err := b.resolveTarget(elt, value)
+		if err != nil {
+			return err
+		}
+		target = resolved
+	}
+
+	return b.exchange(exchange{
+		elt:      elt,
+		method:   method,
+		path:     path,
+		values:   values,
+		target:   target,
+		strategy: b.strategy(elt),
+		selector: inheritedOrEmpty(elt, string(htmx.Select)),
+		pushURL:  "true",
+		boosted:  true,
+		headers:  nil,
+	})
+}
+
+// exchange sends an htmx request, and applies the response.
+func (b *Browser) exchange(ex exchange) error {
+	req, err := b.newRequest(ex.method, ex.path, ex.values)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(string(hxreq.Request), "true")
+	req.Header.Set(string(hxreq.CurrentURL), b.location.String())
+	if ex.boosted {
+		req.Header.Set(string(hxreq.Boosted), "true")
+	}
+	if id := getAttr(ex.target, "id"); id != "" {
+		req.Header.Set(string(hxreq.Target), id)
+	}
+	if id := getAttr(ex.elt, "id"); id != "" {
+		req.Header.Set(string(hxreq.Trigger), id)
+	}
+	if name := getAttr(ex.elt, "name"); name != "" {
+		req.Header.Set(string(hxreq.TriggerName), name)
+	}
+	for name, value := range ex.headers {
+		req.Header.Set(name, value)
+	}
+
+	w := b.serve(req)
+	h := w.Header()
+
+	if location := h.Get(string(hxres.Redirect)); location != "" {
+		return b.navigate(http.MethodGet, location, nil)
+	}
+	if h.Get(string(hxres.Refresh)) == "true" {
+		return b.navigate(http.MethodGet, b.location.String(), nil)
+	}
+	if location := h.Get(string(hxres.Location)); location != "" {
+		return b.ajaxLocation(location)
+	}
+
+	if !b.shouldSwap(w.Code) {
+		return nil
+	}
+
+	if value := h.Get(string(hxres.Retarget)); value != "" {
+		target, err := b.resolveTarget(ex.elt, value)
+		if err != nil {
+			return err
+		}
+		ex.target = target
+	}
+	if value := h.Get(string(hxres.Reswap)); value != "" {
+		ex.strategy = parseStrategy(value)
+	}
+	if value := h.Get(string(hxres.Reselect)); value != "" {
+		ex.selector = value
+	}
+
+	if err := b.swapResponse(ex, w.Body.String()); err != nil {
+		return err
+	}
+
+	return b.updateLocation(req.URL, ex.pushURL, h)
+}
+
+// updateLocation updates the current URL from hx-push-url, and the push and replace headers.
+func (b *Browser) updateLocation(requestURL *url.URL, pushURL string, h http.Header) error {
+	value := pushURL
+	for _, header := range []hxres.Header{hxres.PushURL, hxres.ReplaceURL} {
+		if v := h.Get(string(header)); v != "" {
+			value = v
+		}
+	}
+
+	switch value {
+	case "", "false":
+		return nil
+	case "true":
+		b.location = requestURL
+		return nil
+	default:
+		u, err := b.location.Parse(value)
+		if err != nil {
+			return err
+		}
+		b.location = u
+		return nil
+	}
+}
+
+// shouldSwap checks if a response with the status code is swapped in.
+func (b *Browser) shouldSwap(code int) bool {
+	if b.swapStatus[code] {
+		return true
+	}
+	return code >= 200 && code < 300 && code != http.StatusNoContent
+}
+
+// newRequest builds a request relative to the current URL, with the values in the query string or form body.
+func (b *Browser) newRequest(method string, path string, values url.Values) (*http.Request, error) {
+	u, err := b.location.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if method == http.MethodGet || method == http.MethodDelete && b.version >= htmx.V2 {
+		if len(values) > 0 {
+			query := u.Query()
+			for key, vs := range values {
+				query[key] = append(query[key], vs...)
+			}
+			u.RawQuery = query.Encode()
+		}
+	} else {
+		body = strings.NewReader(values.Encode())
+	}
+
+	req := httptest.NewRequest(method, u.String(), body)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return req, nil
+}
+
+// serve sends a request to the handler, and records the response.
+func (b *Browser) serve(req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	b.handler.ServeHTTP(w, req)
+	b.response = w
+	return w
+}
+
+// target finds the element to swap for a request, from hx-target or the element itself.
+func (b *Browser) target(elt *html.Node) (*html.Node, error) {
+	value, declaredOn := inheritedAttrNode(elt, string(htmx.Target))
+	if declaredOn == nil {
+		return elt, nil
+	}
+	if value == string(htmx.TargetThis) {
+		return declaredOn, nil
+	}
+	return b.resolveTarget(elt, value)
+}
+
+// resolveTarget resolves an extended selector to a single element.
+func (b *Browser) resolveTarget(elt *html.Node, selector string) (*html.Node, error) {
+	nodes, err := b.resolve(elt, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, selector)
+	}
+	return nodes[0], nil
+}
+
+// strategy returns the swap strategy for an element, from hx-swap or the default.
+func (b *Browser) strategy(elt *html.Node) swap.Strategy {
+	return parseStrategy(inheritedOrEmpty(elt, string(htmx.Swap)))
+}
+
+// parseStrategy reads the strategy from an hx-swap value, ignoring modifiers.
+func parseStrategy(value string) swap.Strategy {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return swap.InnerHTML
+	}
+	return swap.Strategy(fields[0])
+}
+
+// ajaxLocation follows an HX-Location header the way htmx.ajax() does, with a GET request for the path, swapped using the header's context.
+// Without a source or target, the response is swapped into the body.
+func (b *Browser) ajaxLocation(value string) error {
+	location, err := parseLocation(value)
+	if err != nil {
+		return err
+	}
+	if location.Handler != "" {
+		return fmt.Errorf("%w: HX-Location handler", ErrUnsupported)
+	}
+
+	elt := b.body()
+	if location.Source != "" {
+		if elt, err = b.Find(location.Source); err != nil {
+			return err
+		}
+	}
+
+	target, err := b.target(elt)
+	if location.Target != "" {
+		target, err = b.resolveTarget(elt, location.Target)
+	}
+	if err != nil {
+		return err
+	}
+
+	strategy := b.strategy(elt)
+	if location.Swap != "" {
+		strategy = parseStrategy(location.Swap)
+	}
+	selector := inheritedOrEmpty(elt, string(htmx.Select))
+	if location.Select != "" {
+		selector = location.Select
+	}
+
+	values, err := b.values(elt, "get", nil)
+	if err != nil {
+		return err
+	}
+	for key, value := range jsonValues(location.Values) {
+		values.Set(key, value)
+	}
+
+	return b.exchange(exchange{
+		elt:      elt,
+		method:   http.MethodGet,
+		path:     location.Path,
+		values:   values,
+		target:   target,
+		strategy: strategy,
+		selector: selector,
+		pushURL:  location.Path,
+		boosted:  false,
+		headers:  location.Headers,
+	})
+}
+
+// locationContext is the context object of an HX-Location header.
+type locationContext struct {
+	Path    string            `json:"path"`
+	Source  string            `json:"source"`
+	Event   string            `json:"event"`
+	Handler string            `json:"handler"`
+	Target  string            `json:"target"`
+	Swap    string            `json:"swap"`
+	Values  map[string]any    `json:"values"`
+	Headers map[string]string `json:"headers"`
+	Select  string            `json:"select"`
+}
+
+// parseLocation reads an HX-Location value, which may be a path or a JSON context object.
+func parseLocation(value string) (locationContext, error) {
+	var location locationContext
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		location.Path = value
+		return location, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&location); err != nil {
+		return location, fmt.Errorf("htmxtest: invalid HX-Location %q: %w", value, err)
+	}
+	return location, nil
+}
+
+// body returns the body element of the page.
+func (b *Browser) body() *html.Node {
+	for _, n := range descendants(b.doc) {
+		if n.DataAtom == atom.Body {
+			return n
+		}
+	}
+	return b.doc
+}
@@ -0,0 +1,282 @@
+package htmxtest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/htmxtest"
+	"github.com/will-wow/typed-htmx-go/htmx/hxreq"
+	"github.com/will-wow/typed-htmx-go/htmx/hxres"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+func app() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `<!DOCTYPE html><html><body hx-boost="true">
+			<span id="count">0</span>
+			<ul id="todos"></ul>
+			<div id="row" hx-target="this" hx-swap="outerHTML">
+				<button id="edit" hx-get="/edit">Edit</button>
+			</div>
+			<form hx-post="/todos" hx-target="#todos" hx-swap="beforeend" hx-vals='{"list": 1}'>
+				<input name="title" value="">
+				<input type="checkbox" name="done" value="yes">
+				<select name="priority"><option>low</option><option>high</option></select>
+				<button type="submit" name="action" value="add">Add</button>
+			</form>
+			<input id="search" name="q" value="milk">
+			<button id="find" hx-get="/search" hx-include="#search" hx-target="next ul" hx-select="li">Search</button>
+			<ul></ul>
+			<a id="about" href="/about">About</a>
+			<span id="status"></span>
+			<button id="like" data-hx-post="/like" data-hx-target="#count">Like</button>
+			<button id="finish" hx-post="/finish">Finish</button>
+			<button id="save" hx-post="/save">Save</button>
+			<button id="script" hx-post="/script">Script</button>
+			<button id="remove" hx-delete="/items" hx-vals='{"id": 3}' hx-target="#status">Remove</button>
+		</body></html>`)
+	})
+	mux.HandleFunc("/edit", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `<form id="row" hx-put="/row" hx-target="this" hx-swap="outerHTML"><input name="name" value="Joe"></form>`)
+	})
+	mux.HandleFunc("/row", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("name") == "" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = fmt.Fprint(w, `<form id="row"><p class="error">Name is required</p></form>`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `<div id="row">%s</div>`, r.FormValue("name"))
+	})
+	mux.HandleFunc("/todos", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		_, _ = fmt.Fprintf(w, `<li>%s %s %s %s %s</li><span id="count" hx-swap-oob="true">1</span>`,
+			r.PostForm.Get("title"), r.PostForm.Get("done"), r.PostForm.Get("priority"), r.PostForm.Get("action"), r.PostForm.Get("list"))
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<h1>Results</h1><li>%s</li>`, r.URL.Query().Get("q"))
+	})
+	mux.HandleFunc("/like", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `5<span id="status" data-hx-swap-oob="true">liked</span>`)
+	})
+	mux.HandleFunc("DELETE /items", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "query=%s body=%s", r.URL.RawQuery, body)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/about", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/finish", func(w http.ResponseWriter, r *http.Request) {
+		_ = hxres.New().Location("/welcome").Apply(w)
+	})
+	mux.HandleFunc("/welcome", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<h1>Welcome</h1><p id="htmx">%t</p>`, hxreq.Parse(r).Request)
+	})
+	mux.HandleFunc("/save", func(w http.ResponseWriter, r *http.Request) {
+		_ = hxres.New().LocationExtended(
+			hxres.NewLocation("/saved").
+				Target("#status").
+				Swap(swap.OuterHTML).
+				Select("#saved").
+				Values(map[string]any{"count": 2}).
+				Headers(map[string]string{"X-Source": "save"}),
+		).Apply(w)
+	})
+	mux.HandleFunc("/saved", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<p>Ignored</p><span id="saved">%s from %s</span>`, r.URL.Query().Get("count"), r.Header.Get("X-Source"))
+	})
+	mux.HandleFunc("/script", func(w http.ResponseWriter, r *http.Request) {
+		_ = hxres.New().LocationExtended(hxres.NewLocation("/welcome").Handler("render")).Apply(w)
+	})
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		if hxreq.Parse(r).Boosted {
+			_ = hxres.New().PushURLPath("/about-us").Apply(w)
+		}
+		_, _ = fmt.Fprint(w, `<html><head><title>About</title></head><body><h1>About</h1></body></html>`)
+	})
+	return mux
+}
+
+func ExampleBrowser() {
+	b := htmxtest.NewBrowser(app(), htmx.V2)
+	_ = b.Open("/")
+
+	_ = b.Click("#edit")
+	_ = b.Fill("input[name=name]", "Jane")
+	_ = b.Submit("#row")
+
+	row, _ := b.OuterHTML("#row")
+	fmt.Println(row)
+	// Output: <div id="row">Jane</div>
+}
+
+func TestBrowser(t *testing.T) {
+	t.Run("form values, vals and oob", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+		mustDo(t, b.Fill("input[name=title]", "milk"))
+		mustDo(t, b.Check("input[name=done]", true))
+		mustDo(t, b.Fill("select[name=priority]", "high"))
+		mustDo(t, b.Click("button[type=submit]"))
+
+		assertText(t, b, "#todos", "milk yes high add 1")
+		assertText(t, b, "#count", "1")
+	})
+
+	t.Run("include, relative target and select", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+		mustDo(t, b.Click("#find"))
+
+		html, err := b.OuterHTML("#find + ul")
+		mustDo(t, err)
+		if html != `<ul><li>milk</li></ul>` {
+			t.Errorf("unexpected search results %s", html)
+		}
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+		mustDo(t, b.Click("#edit"))
+		mustDo(t, b.Fill("input[name=name]", ""))
+		mustDo(t, b.Submit("#row"))
+
+		if b.Response().Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status 422, got %d", b.Response().Code)
+		}
+		if _, err := b.Find(".error"); !errors.Is(err, htmxtest.ErrNotFound) {
+			t.Errorf("expected the error response not to be swapped, got %v", err)
+		}
+
+		b.SwapStatus(http.StatusUnprocessableEntity)
+		mustDo(t, b.Submit("#row"))
+		assertText(t, b, ".error", "Name is required")
+	})
+
+	t.Run("boost", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+		mustDo(t, b.Click("#about"))
+
+		assertText(t, b, "body", "About")
+		if b.URL() != "http://example.com/about-us" {
+			t.Errorf("expected the pushed URL, got %s", b.URL())
+		}
+	})
+
+	t.Run("data- prefixed attributes", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+		mustDo(t, b.Click("#like"))
+
+		assertText(t, b, "#count", "5")
+		assertText(t, b, "#status", "liked")
+	})
+
+	t.Run("delete values", func(t *testing.T) {
+		tests := []struct {
+			version htmx.Version
+			want    string
+		}{
+			{version: htmx.V1, want: "query= body=id=3"},
+			{version: htmx.V2, want: "query=id=3 body="},
+		}
+		for _, tt := range tests {
+			b := htmxtest.NewBrowser(app(), tt.version)
+			mustDo(t, b.Open("/"))
+			mustDo(t, b.Click("#remove"))
+			assertText(t, b, "#status", tt.want)
+		}
+	})
+
+	t.Run("location swaps into the body", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+		mustDo(t, b.Click("#finish"))
+
+		assertText(t, b, "h1", "Welcome")
+		assertText(t, b, "#htmx", "true")
+		if b.URL() != "http://example.com/welcome" {
+			t.Errorf("expected the location to be pushed, got %s", b.URL())
+		}
+		if body, _ := b.Find("body[hx-boost]"); body == nil {
+			t.Error("expected the page to be kept, not reloaded")
+		}
+	})
+
+	t.Run("location context", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+		mustDo(t, b.Click("#save"))
+
+		html, err := b.OuterHTML("#saved")
+		mustDo(t, err)
+		if html != `<span id="saved">2 from save</span>` {
+			t.Errorf("unexpected swap %s", html)
+		}
+		if _, err := b.Find("#status"); !errors.Is(err, htmxtest.ErrNotFound) {
+			t.Errorf("expected the target to be replaced, got %v", err)
+		}
+		assertText(t, b, "#count", "0")
+	})
+
+	t.Run("location handler", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+
+		if err := b.Click("#script"); !errors.Is(err, htmxtest.ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	})
+
+	t.Run("redirects", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/old"))
+		assertText(t, b, "h1", "About")
+		if b.URL() != "http://example.com/about" {
+			t.Errorf("expected the redirected URL, got %s", b.URL())
+		}
+
+		if err := b.Open("/loop"); !errors.Is(err, htmxtest.ErrTooManyRedirects) {
+			t.Errorf("expected ErrTooManyRedirects, got %v", err)
+		}
+	})
+
+	t.Run("no request", func(t *testing.T) {
+		b := htmxtest.NewBrowser(app(), htmx.V2)
+		mustDo(t, b.Open("/"))
+
+		if err := b.Click("#count"); !errors.Is(err, htmxtest.ErrNoRequest) {
+			t.Errorf("expected ErrNoRequest, got %v", err)
+		}
+		if err := b.Click("#missing"); !errors.Is(err, htmxtest.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertText(t *testing.T, b *htmxtest.Browser, selector string, want string) {
+	t.Helper()
+	got, err := b.Text(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("expected %s text %q, got %q", selector, want, got)
+	}
+}
@@ -0,0 +1,643 @@
+package htmxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+// requestAttrs are the attributes that make a request, in the order htmx checks them.
+var requestAttrs = []htmx.Attribute{htmx.Get, htmx.Post, htmx.Put, htmx.Patch, htmx.Delete}
+
+// requestAttr returns the method and path of the request an element makes.
+func requestAttr(n *html.Node) (method string, path string, ok bool) {
+	for _, attr := range requestAttrs {
+		if value, found := lookupAttr(n, string(attr)); found {
+			return strings.ToUpper(strings.TrimPrefix(string(attr), "hx-")), value, true
+		}
+	}
+	return "", "", false
+}
+
+// swapResponse parses the response, applies its out-of-band swaps, and swaps the rest into the target.
+func (b *Browser) swapResponse(ex exchange, body string) error {
+	context := ex.target
+	switch ex.strategy {
+	case swap.OuterHTML, swap.BeforeBegin, swap.AfterEnd:
+		context = ex.target.Parent
+	}
+
+	nodes, err := parseResponse(body, context)
+	if err != nil {
+		return err
+	}
+
+	nodes, oobs := extractOOB(nodes)
+	for _, n := range oobs {
+		if err := b.swapOOB(n); err != nil {
+			return err
+		}
+	}
+
+	if ex.selector != "" {
+		nodes, err = selectNodes(nodes, ex.selector)
+		if err != nil {
+			return err
+		}
+	}
+
+	return swapNodes(ex.target, ex.strategy, nodes)
+}
+
+// parseResponse parses a response body into nodes. Full documents are reduced to the contents of their body.
+func parseResponse(body string, context *html.Node) ([]*html.Node, error) {
+	start := strings.ToLower(strings.TrimSpace(body))
+	for _, prefix := range []string{"<!doctype", "<html", "<head", "<body"} {
+		if strings.HasPrefix(start, prefix) {
+			doc, err := html.Parse(strings.NewReader(body))
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range descendants(doc) {
+				if n.DataAtom == atom.Body {
+					return detachChildren(n), nil
+				}
+			}
+			return nil, nil
+		}
+	}
+
+	if context == nil || context.Type != html.ElementNode {
+		context = &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	}
+	return html.ParseFragment(strings.NewReader(body), context)
+}
+
+// extractOOB separates top-level out-of-band elements, including those wrapped in a <template>, from the rest of the response.
+func extractOOB(nodes []*html.Node) (rest []*html.Node, oobs []*html.Node) {
+	for _, n := range nodes {
+		switch {
+		case hasAttr(n, string(htmx.SwapOOB)):
+			oobs = append(oobs, n)
+		case n.DataAtom == atom.Template:
+			kept := false
+			for _, child := range detachChildren(n) {
+				if hasAttr(child, string(htmx.SwapOOB)) {
+					oobs = append(oobs, child)
+				} else {
+					n.AppendChild(child)
+					kept = true
+				}
+			}
+			if kept {
+				rest = append(rest, n)
+			}
+		default:
+			rest = append(rest, n)
+		}
+	}
+	return rest, oobs
+}
+
+// swapOOB swaps an out-of-band element into the page, as set by its hx-swap-oob attribute.
+func (b *Browser) swapOOB(n *html.Node) error {
+	value := getAttr(n, string(htmx.SwapOOB))
+	removeAttr(n, string(htmx.SwapOOB))
+	removeAttr(n, "data-"+string(htmx.SwapOOB))
+
+	strategy := swap.OuterHTML
+	var targets []*html.Node
+	if before, selector, found := strings.Cut(value, ":"); found {
+		strategy = parseStrategy(before)
+		nodes, err := queryAll(b.doc, selector)
+		if err != nil {
+			return err
+		}
+		targets = nodes
+	} else {
+		if value != "true" && value != "" {
+			strategy = parseStrategy(value)
+		}
+		if target := findByID(b.doc, getAttr(n, "id")); target != nil {
+			targets = append(targets, target)
+		}
+	}
+
+	for _, target := range targets {
+		var content []*html.Node
+		if strategy == swap.OuterHTML {
+			content = []*html.Node{cloneNode(n)}
+		} else {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				content = append(content, cloneNode(c))
+			}
+		}
+		if err := swapNodes(target, strategy, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectNodes picks the elements matching a selector out of the response, for hx-select.
+func selectNodes(nodes []*html.Node, selector string) ([]*html.Node, error) {
+	container := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		container.AppendChild(n)
+	}
+
+	matches, err := queryAll(container, selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range matches {
+		n.Parent.RemoveChild(n)
+	}
+	return matches, nil
+}
+
+// swapNodes swaps nodes into the target with a strategy.
+func swapNodes(target *html.Node, strategy swap.Strategy, nodes []*html.Node) error {
+	parent := target.Parent
+
+	switch strategy {
+	case swap.InnerHTML:
+		removeChildren(target)
+		appendAll(target, nil, nodes)
+	case swap.OuterHTML:
+		if parent == nil {
+			return fmt.Errorf("%w: outerHTML swap of the document", ErrUnsupported)
+		}
+		appendAll(parent, target, nodes)
+		parent.RemoveChild(target)
+	case swap.BeforeBegin:
+		if parent != nil {
+			appendAll(parent, target, nodes)
+		}
+	case swap.AfterBegin:
+		appendAll(target, target.FirstChild, nodes)
+	case swap.BeforeEnd:
+		appendAll(target, nil, nodes)
+	case swap.AfterEnd:
+		if parent != nil {
+			appendAll(parent, target.NextSibling, nodes)
+		}
+	case swap.Delete:
+		if parent != nil {
+			parent.RemoveChild(target)
+		}
+	case swap.None:
+	default:
+		return fmt.Errorf("%w: swap strategy %q", ErrUnsupported, strategy)
+	}
+	return nil
+}
+
+// appendAll inserts nodes into parent before a sibling, or at the end if before is nil.
+func appendAll(parent *html.Node, before *html.Node, nodes []*html.Node) {
+	for _, n := range nodes {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+		parent.InsertBefore(n, before)
+	}
+}
+
+// resolve finds the elements matching an extended selector, like those used by hx-target and hx-include.
+func (b *Browser) resolve(elt *html.Node, selector string) ([]*html.Node, error) {
+	selector = strings.TrimSpace(selector)
+	keyword, rest, _ := strings.Cut(selector, " ")
+	rest = strings.TrimSpace(rest)
+
+	switch {
+	case selector == string(htmx.TargetThis):
+		return []*html.Node{elt}, nil
+	case selector == "next":
+		return nonNil(nextElement(elt)), nil
+	case selector == "previous":
+		return nonNil(previousElement(elt)), nil
+	case keyword == "closest":
+		m, err := cascadia.ParseGroup(rest)
+		if err != nil {
+			return nil, err
+		}
+		return nonNil(closest(elt, m.Match)), nil
+	case keyword == "find":
+		m, err := cascadia.ParseGroup(rest)
+		if err != nil {
+			return nil, err
+		}
+		return nonNil(cascadia.Query(elt, m)), nil
+	case keyword == "next" || keyword == "previous":
+		matches, err := queryAll(b.doc, rest)
+		if err != nil {
+			return nil, err
+		}
+		return nonNil(relative(b.doc, elt, matches, keyword == "next")), nil
+	default:
+		return queryAll(b.doc, selector)
+	}
+}
+
+// relative finds the first match after elt, or the last match before it, in document order.
+func relative(doc *html.Node, elt *html.Node, matches []*html.Node, next bool) *html.Node {
+	position := map[*html.Node]int{}
+	for i, n := range descendants(doc) {
+		position[n] = i
+	}
+
+	var found *html.Node
+	for _, n := range matches {
+		if next && position[n] > position[elt] {
+			return n
+		}
+		if !next && position[n] < position[elt] {
+			found = n
+		}
+	}
+	return found
+}
+
+// values collects the values sent with a request, like htmx: the element's own value, its form for non-GET requests, hx-include and hx-vals.
+func (b *Browser) values(elt *html.Node, verb string, submitter *html.Node) (url.Values, error) {
+	values := url.Values{}
+	processed := map[*html.Node]bool{}
+
+	if elt.DataAtom == atom.Form {
+		addInputs(values, elt, processed)
+	} else {
+		if verb != "get" {
+			if form := closest(elt, isForm); form != nil {
+				addInputs(values, form, processed)
+			}
+		}
+		addInput(values, elt, processed)
+	}
+
+	if submitter != nil && getAttr(submitter, "name") != "" {
+		values.Add(getAttr(submitter, "name"), getAttr(submitter, "value"))
+	}
+
+	if include, ok := inheritedAttr(elt, string(htmx.Include)); ok {
+		nodes, err := b.resolve(elt, include)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range nodes {
+			addInputs(values, n, processed)
+		}
+	}
+
+	vals, err := inheritedVals(elt)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range vals {
+		values.Set(key, value)
+	}
+
+	return values, nil
+}
+
+// inheritedVals merges the hx-vals of an element and its ancestors, with closer values taking precedence.
+func inheritedVals(elt *html.Node) (map[string]string, error) {
+	var chain []string
+	for n := elt; n != nil; n = n.Parent {
+		if value, ok := lookupAttr(n, string(htmx.Vals)); ok {
+			chain = append(chain, value)
+		}
+	}
+
+	vals := map[string]string{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		value := strings.TrimSpace(chain[i])
+		if strings.HasPrefix(value, "js:") || strings.HasPrefix(value, "javascript:") {
+			return nil, fmt.Errorf("%w: javascript hx-vals", ErrUnsupported)
+		}
+		if !strings.HasPrefix(value, "{") {
+			value = "{" + value + "}"
+		}
+
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.UseNumber()
+		var obj map[string]any
+		if err := decoder.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("htmxtest: invalid hx-vals %q: %w", chain[i], err)
+		}
+		for key, v := range jsonValues(obj) {
+			vals[key] = v
+		}
+	}
+	return vals, nil
+}
+
+// jsonValues converts a decoded JSON object to form values, the way htmx submits them: strings and numbers as-is, and nested objects as JSON.
+func jsonValues(obj map[string]any) map[string]string {
+	vals := make(map[string]string, len(obj))
+	for key, v := range obj {
+		switch v := v.(type) {
+		case string:
+			vals[key] = v
+		case json.Number, bool, nil:
+			vals[key] = fmt.Sprint(v)
+		default:
+			encoded, _ := json.Marshal(v)
+			vals[key] = string(encoded)
+		}
+	}
+	return vals
+}
+
+// addInputs adds the values of every input in root, including root itself.
+func addInputs(values url.Values, root *html.Node, processed map[*html.Node]bool) {
+	addInput(values, root, processed)
+	for _, n := range descendants(root) {
+		addInput(values, n, processed)
+	}
+}
+
+// addInput adds the value of a named input, select, or textarea, the way a browser submits it.
+func addInput(values url.Values, n *html.Node, processed map[*html.Node]bool) {
+	if processed[n] {
+		return
+	}
+	processed[n] = true
+
+	name := getAttr(n, "name")
+	if name == "" || hasAttr(n, "disabled") {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Input:
+		switch strings.ToLower(getAttr(n, "type")) {
+		case "checkbox", "radio":
+			if hasAttr(n, "checked") {
+				value, ok := lookupAttr(n, "value")
+				if !ok {
+					value = "on"
+				}
+				values.Add(name, value)
+			}
+		case "submit", "button", "reset", "image", "file":
+		default:
+			values.Add(name, getAttr(n, "value"))
+		}
+	case atom.Textarea:
+		values.Add(name, textContent(n))
+	case atom.Select:
+		opts := options(n)
+		selected := false
+		for _, option := range opts {
+			if hasAttr(option, "selected") {
+				values.Add(name, optionValue(option))
+				selected = true
+			}
+		}
+		if !selected && !hasAttr(n, "multiple") && len(opts) > 0 {
+			values.Add(name, optionValue(opts[0]))
+		}
+	}
+}
+
+// queryAll finds the elements matching a CSS selector in the descendants of root.
+func queryAll(root *html.Node, selector string) ([]*html.Node, error) {
+	m, err := cascadia.ParseGroup(selector)
+	if err != nil {
+		return nil, fmt.Errorf("htmxtest: invalid selector %q: %w", selector, err)
+	}
+	return cascadia.QueryAll(root, m), nil
+}
+
+// findByID finds the element with an id.
+func findByID(root *html.Node, id string) *html.Node {
+	if id == "" {
+		return nil
+	}
+	for _, n := range descendants(root) {
+		if getAttr(n, "id") == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// inheritedAttr returns the value of an attribute on an element or its closest ancestor that sets it. A value of "unset" stops the search.
+func inheritedAttr(n *html.Node, name string) (string, bool) {
+	value, declaredOn := inheritedAttrNode(n, name)
+	return value, declaredOn != nil
+}
+
+// inheritedOrEmpty returns the inherited value of an attribute, or an empty string.
+func inheritedOrEmpty(n *html.Node, name string) string {
+	value, _ := inheritedAttr(n, name)
+	return value
+}
+
+// inheritedAttrNode returns the inherited value of an attribute, and the element it was set on.
+func inheritedAttrNode(n *html.Node, name string) (string, *html.Node) {
+	for c := n; c != nil; c = c.Parent {
+		if value, ok := lookupAttr(c, name); ok {
+			if value == "unset" {
+				return "", nil
+			}
+			return value, c
+		}
+	}
+	return "", nil
+}
+
+// isBoosted checks if an element is inside an hx-boost="true" element.
+func isBoosted(n *html.Node) bool {
+	return inheritedOrEmpty(n, string(htmx.Boost)) == "true"
+}
+
+// isSubmitButton checks if an element submits its form when clicked.
+func isSubmitButton(n *html.Node) bool {
+	kind := strings.ToLower(getAttr(n, "type"))
+	switch n.DataAtom {
+	case atom.Button:
+		return kind == "" || kind == "submit"
+	case atom.Input:
+		return kind == "submit" || kind == "image"
+	default:
+		return false
+	}
+}
+
+func isForm(n *html.Node) bool {
+	return n.DataAtom == atom.Form
+}
+
+// closest finds the element or its closest ancestor that matches.
+func closest(n *html.Node, match func(*html.Node) bool) *html.Node {
+	for c := n; c != nil; c = c.Parent {
+		if c.Type == html.ElementNode && match(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+func nextElement(n *html.Node) *html.Node {
+	for c := n.NextSibling; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			return c
+		}
+	}
+	return nil
+}
+
+func previousElement(n *html.Node) *html.Node {
+	for c := n.PrevSibling; c != nil; c = c.PrevSibling {
+		if c.Type == html.ElementNode {
+			return c
+		}
+	}
+	return nil
+}
+
+// nonNil wraps a node in a slice, or returns an empty slice for nil.
+func nonNil(n *html.Node) []*html.Node {
+	if n == nil {
+		return nil
+	}
+	return []*html.Node{n}
+}
+
+// descendants returns every element below n, in document order.
+func descendants(n *html.Node) []*html.Node {
+	var nodes []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				nodes = append(nodes, c)
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	return nodes
+}
+
+// options returns the option elements of a select.
+func options(n *html.Node) []*html.Node {
+	var opts []*html.Node
+	for _, c := range descendants(n) {
+		if c.DataAtom == atom.Option {
+			opts = append(opts, c)
+		}
+	}
+	return opts
+}
+
+// optionValue returns an option's value, or its text if it has no value.
+func optionValue(n *html.Node) string {
+	if value, ok := lookupAttr(n, "value"); ok {
+		return value
+	}
+	return strings.TrimSpace(textContent(n))
+}
+
+// textContent returns the text of a node and its descendants.
+func textContent(n *html.Node) string {
+	var buf bytes.Buffer
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			_, _ = buf.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return buf.String()
+}
+
+// cloneNode deep copies a node, without its parent or siblings.
+func cloneNode(n *html.Node) *html.Node {
+	clone := &html.Node{
+		Type:      n.Type,
+		DataAtom:  n.DataAtom,
+		Data:      n.Data,
+		Namespace: n.Namespace,
+		Attr:      append([]html.Attribute(nil), n.Attr...),
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		clone.AppendChild(cloneNode(c))
+	}
+	return clone
+}
+
+// detachChildren removes and returns the children of a node.
+func detachChildren(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		n.RemoveChild(c)
+		children = append(children, c)
+		c = next
+	}
+	return children
+}
+
+func removeChildren(n *html.Node) {
+	_ = detachChildren(n)
+}
+
+func lookupAttr(n *html.Node, name string) (string, bool) {
+	if n == nil || n.Type != html.ElementNode {
+		return "", false
+	}
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == name {
+			return a.Val, true
+		}
+	}
+	// Like htmx, fall back to the data- prefixed form of an htmx attribute.
+	if strings.HasPrefix(name, "hx-") {
+		return lookupAttr(n, "data-"+name)
+	}
+	return "", false
+}
+
+func getAttr(n *html.Node, name string) string {
+	value, _ := lookupAttr(n, name)
+	return value
+}
+
+func hasAttr(n *html.Node, name string) bool {
+	_, ok := lookupAttr(n, name)
+	return ok
+}
+
+func setAttr(n *html.Node, name string, value string) {
+	for i, a := range n.Attr {
+		if a.Namespace == "" && a.Key == name {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Namespace: "", Key: name, Val: value})
+}
+
+func removeAttr(n *html.Node, name string) {
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		if a.Namespace != "" || a.Key != name {
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attr = attrs
+}
@@ -0,0 +1,152 @@
+package htmxtest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/htmxtest"
+	"github.com/will-wow/typed-htmx-go/htmx/hxreq"
+	"github.com/will-wow/typed-htmx-go/htmx/hxres"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+func ExampleNewRequest() {
+	r := htmxtest.NewRequest(http.MethodPost, "/contacts/1", nil).
+		Boosted().
+		Target("contact-1").
+		Trigger("save-btn").
+		TriggerName("save").
+		Prompt("yes").
+		CurrentURL("http://localhost/contacts").
+		Form(url.Values{"name": {"Joe"}}).
+		Build()
+
+	_ = r.ParseForm()
+	fmt.Printf("%+v\n", hxreq.Parse(r))
+	fmt.Println(r.PostForm.Get("name"))
+	// Output:
+	// {Request:true Boosted:true CurrentURL:http://localhost/contacts HistoryRestoreRequest:false Prompt:yes Target:contact-1 TriggerName:save Trigger:save-btn}
+	// Joe
+}
+
+func ExampleRequestBuilder_Form() {
+	r := htmxtest.NewRequest(http.MethodGet, "/contacts?page=2", nil).
+		Form(url.Values{"q": {"joe"}}).
+		Build()
+
+	fmt.Println(r.URL.String())
+	// Output: /contacts?page=2&q=joe
+}
+
+func ExampleOOB() {
+	fragments := htmxtest.OOB(`
+		<div id="main"></div>
+		<span id="count" hx-swap-oob="true">3</span>
+		<template><tr id="row-1" hx-swap-oob="outerHTML"></tr></template>
+		<li hx-swap-oob="beforeend:#messages">Hi</li>
+	`)
+
+	fmt.Println(fragments)
+	// Output: map[#messages:beforeend:#messages count:true row-1:outerHTML]
+}
+
+type toast struct {
+	Message string `json:"message"`
+}
+
+var showToast = hxres.NewEvent[toast]("showToast")
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	_ = hxres.New().
+		Trigger("saved").
+		TriggerDetail(showToast.With(toast{Message: "Saved!"})).
+		Retarget(htmx.TargetRelative(htmx.Closest, "tr")).
+		ReswapExtended(swap.New().Strategy(swap.OuterHTML).Settle(time.Second)).
+		Apply(w)
+	_, _ = w.Write([]byte(`<tr id="row"></tr><span id="count" hx-swap-oob="true">3</span>`))
+}
+
+func TestAssertions(t *testing.T) {
+	w := httptest.NewRecorder()
+	handler(w, htmxtest.NewRequest(http.MethodPost, "/rows/1", nil).Target("row").Build())
+
+	htmxtest.AssertTrigger(t, w, "saved", showToast.Name())
+	htmxtest.AssertTriggerDetail(t, w, showToast, toast{Message: "Saved!"})
+	htmxtest.AssertRetarget(t, w, htmx.TargetRelative(htmx.Closest, "tr"))
+	htmxtest.AssertReswap(t, w, swap.New().Strategy(swap.OuterHTML).Settle(time.Second))
+	htmxtest.AssertOOB(t, w, "count")
+
+	stop := httptest.NewRecorder()
+	stop.WriteHeader(hxres.StatusStopPolling)
+	htmxtest.AssertStopPolling(t, stop)
+}
+
+// recordingTB records errors instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertionFailures(t *testing.T) {
+	w := httptest.NewRecorder()
+	handler(w, htmxtest.NewRequest(http.MethodPost, "/rows/1", nil).Build())
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		want   string
+	}{
+		{
+			name:   "missing trigger",
+			assert: func(t testing.TB) { htmxtest.AssertTrigger(t, w, "deleted") },
+			want:   `expected HX-Trigger to include "deleted"`,
+		},
+		{
+			name:   "wrong detail",
+			assert: func(t testing.TB) { htmxtest.AssertTriggerDetail(t, w, showToast, toast{Message: "Deleted!"}) },
+			want:   `expected "showToast" detail {Message:Deleted!}, got {Message:Saved!}`,
+		},
+		{
+			name:   "wrong reswap",
+			assert: func(t testing.TB) { htmxtest.AssertReswap(t, w, swap.New().Strategy(swap.InnerHTML)) },
+			want:   `expected HX-Reswap "innerHTML", got "outerHTML settle:1s"`,
+		},
+		{
+			name:   "missing oob",
+			assert: func(t testing.TB) { htmxtest.AssertOOB(t, w, "row") },
+			want:   `expected an out-of-band fragment with id "row"`,
+		},
+		{
+			name:   "not stopped",
+			assert: func(t testing.TB) { htmxtest.AssertStopPolling(t, w) },
+			want:   `expected status 286, got 200`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingTB{TB: t, errors: nil}
+			tt.assert(rec)
+
+			if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], tt.want) {
+				t.Errorf("expected one error containing %q, got %q", tt.want, rec.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package htmxtest
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+)
+
+// OOB finds the out-of-band fragments in an HTML body, including fragments wrapped in a <template>.
+// It returns a map of each fragment's id to its hx-swap-oob value.
+//
+// Fragments that use a selector, like `beforeend:#messages`, may not have an id. They are keyed by their selector instead.
+func OOB(body string) map[string]string {
+	fragments := map[string]string{}
+
+	z := html.NewTokenizer(strings.NewReader(body))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return fragments
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+
+			value, ok := attr(token, string(htmx.SwapOOB))
+			if !ok {
+				continue
+			}
+
+			id, _ := attr(token, "id")
+			if _, selector, found := strings.Cut(value, ":"); found && id == "" {
+				id = selector
+			}
+			fragments[id] = value
+		}
+	}
+}
+
+// attr returns the value of an attribute on a token.
+func attr(token html.Token, name string) (string, bool) {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	// Like htmx, fall back to the data- prefixed form of an htmx attribute.
+	if strings.HasPrefix(name, "hx-") {
+		return attr(token, "data-"+name)
+	}
+	return "", false
+}
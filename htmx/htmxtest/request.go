@@ -0,0 +1,104 @@
+// package htmxtest provides utilities for testing handlers that serve htmx requests, alongside [httptest].
+//
+// Build requests the way htmx would send them, and assert on the htmx response headers and out-of-band fragments:
+//
+//	r := htmxtest.NewRequest(http.MethodPost, "/contacts/1", nil).
+//		Target("contact-1").
+//		Trigger("save-btn").
+//		Build()
+//	w := httptest.NewRecorder()
+//	handler.ServeHTTP(w, r)
+//
+//	htmxtest.AssertTrigger(t, w, "contact-saved")
+//	htmxtest.AssertRetarget(t, w, htmx.TargetRelative(htmx.Closest, "tr"))
+//	htmxtest.AssertOOB(t, w, "contact-count")
+package htmxtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/will-wow/typed-htmx-go/htmx/hxreq"
+)
+
+// A RequestBuilder builds an incoming server request with the headers htmx sends.
+type RequestBuilder struct {
+	r *http.Request
+}
+
+// NewRequest starts a builder chain for an htmx request, with HX-Request set to true, like [httptest.NewRequest].
+func NewRequest(method, target string, body io.Reader) *RequestBuilder {
+	r := httptest.NewRequest(method, target, body)
+	r.Header.Set(string(hxreq.Request), "true")
+	return &RequestBuilder{r: r}
+}
+
+// Form sets the request body to url-encoded form values, like htmx sends for a non-GET request from a form.
+// For GET requests, the values are added to the query string instead.
+func (b *RequestBuilder) Form(values url.Values) *RequestBuilder {
+	if b.r.Method == http.MethodGet {
+		query := b.r.URL.Query()
+		for key, vs := range values {
+			query[key] = append(query[key], vs...)
+		}
+		b.r.URL.RawQuery = query.Encode()
+		b.r.RequestURI = b.r.URL.RequestURI()
+		return b
+	}
+
+	encoded := values.Encode()
+	b.r.Body = io.NopCloser(strings.NewReader(encoded))
+	b.r.ContentLength = int64(len(encoded))
+	b.r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return b
+}
+
+// Boosted marks the request as coming from an element using hx-boost.
+func (b *RequestBuilder) Boosted() *RequestBuilder {
+	b.r.Header.Set(string(hxreq.Boosted), "true")
+	return b
+}
+
+// HistoryRestore marks the request as a history restoration after a miss in the local history cache.
+func (b *RequestBuilder) HistoryRestore() *RequestBuilder {
+	b.r.Header.Set(string(hxreq.HistoryRestoreRequest), "true")
+	return b
+}
+
+// CurrentURL sets the current URL of the browser.
+func (b *RequestBuilder) CurrentURL(url string) *RequestBuilder {
+	b.r.Header.Set(string(hxreq.CurrentURL), url)
+	return b
+}
+
+// Target sets the id of the target element.
+func (b *RequestBuilder) Target(id string) *RequestBuilder {
+	b.r.Header.Set(string(hxreq.Target), id)
+	return b
+}
+
+// Trigger sets the id of the element that triggered the request.
+func (b *RequestBuilder) Trigger(id string) *RequestBuilder {
+	b.r.Header.Set(string(hxreq.Trigger), id)
+	return b
+}
+
+// TriggerName sets the name of the element that triggered the request.
+func (b *RequestBuilder) TriggerName(name string) *RequestBuilder {
+	b.r.Header.Set(string(hxreq.TriggerName), name)
+	return b
+}
+
+// Prompt sets the user's response to an hx-prompt.
+func (b *RequestBuilder) Prompt(response string) *RequestBuilder {
+	b.r.Header.Set(string(hxreq.Prompt), response)
+	return b
+}
+
+// Build returns the built request, ready to be passed to an [http.Handler].
+func (b *RequestBuilder) Build() *http.Request {
+	return b.r
+}
@@ -1,15 +1,36 @@
+// package hxconfig builds the htmx config, which is set on a `<meta name="htmx-config">` element.
+//
+// Every key of the htmx 1.x and 2.x config has a setter, which checks its value when the config is built for a version.
+// Only the keys that differ from that version's defaults are rendered.
+//
+// [HTMX Docs]
+//
+// [HTMX Docs]: https://htmx.org/reference/#config
 package hxconfig
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/andybalholm/cascadia"
+
 	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	"github.com/will-wow/typed-htmx-go/htmx/version"
 )
 
+// ErrInvalid is returned for a config value that htmx can't use, like an empty class name or a negative duration.
+var ErrInvalid = errors.New("hxconfig: invalid value")
+
+// A Builder sets htmx config keys.
 type Builder struct {
 	config map[string]any
 }
 
+// New starts an empty config, which leaves every key at its default.
 func New() *Builder {
 	return &Builder{
 		config: map[string]any{},
@@ -42,17 +63,13 @@ func (b *Builder) DefaultSwapStyle(value swap.Strategy) *Builder {
 
 // defaults to 0
 func (b *Builder) DefaultSwapDelay(value time.Duration) *Builder {
-	// convert to milliseconds
-	ms := int(value / time.Millisecond)
-	b.config["defaultSwapDelay"] = ms
+	b.config["defaultSwapDelay"] = milliseconds(value)
 	return b
 }
 
 // defaults to 20
 func (b *Builder) DefaultSettleDelay(value time.Duration) *Builder {
-	// convert to milliseconds
-	ms := int(value / time.Millisecond)
-	b.config["defaultSettleDelay"] = ms
+	b.config["defaultSettleDelay"] = milliseconds(value)
 	return b
 }
 
@@ -110,26 +127,47 @@ func (b *Builder) InlineScriptNonce(value string) *Builder {
 	return b
 }
 
+// defaults to ”, meaning that no nonce will be added to inline styles. Requires htmx 2.x.
+func (b *Builder) InlineStyleNonce(value string) *Builder {
+	b.config["inlineStyleNonce"] = value
+	return b
+}
+
 // defaults to ["class", "style", "width", "height"], the attributes to settle during the settling phase
 func (b *Builder) AttributesToSettle(value []string) *Builder {
 	b.config["attributesToSettle"] = value
 	return b
 }
 
-// defaults to false, HTML template tags for parsing content from the server (not IE11 compatible!)
+// defaults to false, HTML template tags for parsing content from the server (not IE11 compatible!). Removed in htmx 2.x, which always uses template tags.
 func (b *Builder) UseTemplateFragments(value bool) *Builder {
 	b.config["useTemplateFragments"] = value
 	return b
 }
 
-// defaults to ["get"], htmx will format requests with these methods by encoding their parameters in the URL, not the request body
-func (b *Builder) WSReconnectDelay(value string) *Builder {
+// A ReconnectDelay is the algorithm the ws extension uses to wait before reconnecting a WebSocket.
+type ReconnectDelay string
+
+// ReconnectFullJitter waits a random time that grows exponentially with each retry.
+// htmx also accepts a JavaScript function, which can only be set from a script.
+const ReconnectFullJitter ReconnectDelay = "full-jitter"
+
+// defaults to full-jitter, the delay before the ws extension reconnects a closed WebSocket
+func (b *Builder) WSReconnectDelay(value ReconnectDelay) *Builder {
 	b.config["wsReconnectDelay"] = value
 	return b
 }
 
-// defaults to blob, the the type of binary data being received over the WebSocket connection
-func (b *Builder) WSBinaryType(value string) *Builder {
+// A BinaryType is the type of binary data received over a WebSocket.
+type BinaryType string
+
+const (
+	BinaryTypeBlob        BinaryType = "blob"        // Receive binary data as a Blob.
+	BinaryTypeArrayBuffer BinaryType = "arraybuffer" // Receive binary data as an ArrayBuffer.
+)
+
+// defaults to blob, the type of binary data being received over the WebSocket connection
+func (b *Builder) WSBinaryType(value BinaryType) *Builder {
 	b.config["wsBinaryType"] = value
 	return b
 }
@@ -146,22 +184,21 @@ func (b *Builder) WithCredentials(value bool) *Builder {
 	return b
 }
 
-// defaults to 0, the number of milliseconds a request can take before automatically being terminated
+// defaults to 0, the time a request can take before automatically being terminated. 0 means no timeout.
 func (b *Builder) Timeout(value time.Duration) *Builder {
-	// convert to milliseconds
-	ms := int(value / time.Millisecond)
-	b.config["timeout"] = &ms
+	b.config["timeout"] = milliseconds(value)
 	return b
 }
 
 type ScrollBehavior string
 
 const (
-	ScrollBehaviorAuto   ScrollBehavior = "auto"   // auto will behave like a vanilla link.
-	ScrollBehaviorSmooth ScrollBehavior = "smooth" // smooth (default) will smoothscroll to the top of the page
+	ScrollBehaviorAuto    ScrollBehavior = "auto"    // auto will behave like a vanilla link.
+	ScrollBehaviorSmooth  ScrollBehavior = "smooth"  // smooth (default in htmx 1.x) will smoothscroll to the top of the page
+	ScrollBehaviorInstant ScrollBehavior = "instant" // instant (default in htmx 2.x) will scroll instantly in a single jump. Requires htmx 2.x.
 )
 
-// defaults to ‘smooth’, the behavior for a boosted link on page transitions. The allowed values are auto and smooth. Smooth will smoothscroll to the top of the page while auto will behave like a vanilla link.
+// defaults to ‘smooth’ in htmx 1.x and ‘instant’ in 2.x, the behavior for a boosted link on page transitions. Smooth will smoothscroll to the top of the page while auto will behave like a vanilla link.
 func (b *Builder) ScrollBehavior(value ScrollBehavior) *Builder {
 	b.config["scrollBehavior"] = value
 	return b
@@ -198,13 +235,13 @@ const (
 	MethodOptions HTTPMethod = "options"
 )
 
-// defaults to ["get"], htmx will format requests with these methods by encoding their parameters in the URL, not the request body
+// defaults to ["get"] in htmx 1.x and ["get", "delete"] in 2.x, htmx will format requests with these methods by encoding their parameters in the URL, not the request body
 func (b *Builder) MethodsThatUseUrlParams(value []HTTPMethod) *Builder {
 	b.config["methodsThatUseUrlParams"] = value
 	return b
 }
 
-// defaults to false, if set to true will only allow AJAX requests to the same domain as the current document
+// defaults to false in htmx 1.x and true in 2.x, if set to true will only allow AJAX requests to the same domain as the current document
 func (b *Builder) SelfRequestsOnly(value bool) *Builder {
 	b.config["selfRequestsOnly"] = value
 	return b
@@ -222,12 +259,235 @@ func (b *Builder) ScrollIntoViewOnBoost(value bool) *Builder {
 	return b
 }
 
-// defaults to null, the cache to store evaluated trigger specifications into, improving parsing performance at the cost of more memory usage. You may define a simple object to use a never-clearing cache, or implement your own system using a proxy object
-func (b *Builder) TriggerSpecsCache(value string) *Builder {
-	b.config["triggerSpecsCache"] = value
+// defaults to false, if set to true htmx stores evaluated trigger specifications in a never-clearing cache, improving parsing performance at the cost of more memory usage.
+// A custom cache, like a proxy object, can only be set from a script.
+func (b *Builder) TriggerSpecsCache(value bool) *Builder {
+	if value {
+		b.config["triggerSpecsCache"] = struct{}{}
+	} else {
+		b.config["triggerSpecsCache"] = nil
+	}
+	return b
+}
+
+// defaults to false, if set to true, attributes are only inherited by elements that opt in with hx-inherit. Requires htmx 2.x.
+func (b *Builder) DisableInheritance(value bool) *Builder {
+	b.config["disableInheritance"] = value
+	return b
+}
+
+// defaults to true, whether to process out of band swaps in elements that are themselves swapped out of band. Requires htmx 2.x.
+func (b *Builder) AllowNestedOobSwaps(value bool) *Builder {
+	b.config["allowNestedOobSwaps"] = value
 	return b
 }
 
+// Build returns every key that was set, whether or not it's valid or differs from the default.
+// Use [Builder.BuildFor] for the config to send to htmx.
 func (b *Builder) Build() map[string]any {
 	return b.config
 }
+
+// BuildFor returns the config for a version of htmx, with only the keys that differ from that version's defaults.
+// Keys and values that don't exist in that version are left out, and returned as an error wrapping [version.ErrUnsupported].
+// Invalid values are left out, and returned as an error wrapping [ErrInvalid].
+func (b *Builder) BuildFor(v version.Version) (map[string]any, error) {
+	config := make(map[string]any, len(b.config))
+	var errs []error
+	for name, value := range b.config {
+		k := keys[name]
+		def, ok := k.defaults[v]
+		if !ok {
+			errs = append(errs, version.Unsupported(fmt.Sprintf("config %s", name), v))
+			continue
+		}
+		if err := k.validate(name, value, v); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if equal(value, def) {
+			continue
+		}
+		config[name] = value
+	}
+
+	// Sort the errors so they're reported in a consistent order.
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+	return config, errors.Join(errs...)
+}
+
+// JSON returns the config for a version of htmx as JSON, for the content of the meta element.
+// It returns the same errors as [Builder.BuildFor], along with the valid keys.
+// If the valid keys can't be marshaled, it returns an empty string.
+func (b *Builder) JSON(v version.Version) (string, error) {
+	config, err := b.BuildFor(v)
+	bytes, jsonErr := json.Marshal(config)
+	if jsonErr != nil {
+		return "", errors.Join(err, jsonErr)
+	}
+	return string(bytes), err
+}
+
+// A key describes an htmx config key.
+type key struct {
+	// defaults are the default values of the key, for each version that supports it.
+	defaults map[version.Version]any
+	// validate checks a value set for the key.
+	validate func(name string, value any, v version.Version) error
+}
+
+// both returns the defaults for a key that's the same in every version.
+func both(value any) map[version.Version]any {
+	return map[version.Version]any{version.V1: value, version.V2: value}
+}
+
+// keys are every key in the htmx config, with their defaults in htmx 1.9.12 and 2.0.2.
+var keys = map[string]key{
+	"historyEnabled":         {defaults: both(true), validate: valid},
+	"historyCacheSize":       {defaults: both(10), validate: nonNegative},
+	"refreshOnHistoryMiss":   {defaults: both(false), validate: valid},
+	"defaultSwapStyle":       {defaults: both(swap.InnerHTML), validate: validStrategy},
+	"defaultSwapDelay":       {defaults: both(0), validate: nonNegative},
+	"defaultSettleDelay":     {defaults: both(20), validate: nonNegative},
+	"includeIndicatorStyles": {defaults: both(true), validate: valid},
+	"indicatorClass":         {defaults: both("htmx-indicator"), validate: validClass},
+	"requestClass":           {defaults: both("htmx-request"), validate: validClass},
+	"addedClass":             {defaults: both("htmx-added"), validate: validClass},
+	"settlingClass":          {defaults: both("htmx-settling"), validate: validClass},
+	"swappingClass":          {defaults: both("htmx-swapping"), validate: validClass},
+	"allowEval":              {defaults: both(true), validate: valid},
+	"allowScriptTags":        {defaults: both(true), validate: valid},
+	"inlineScriptNonce":      {defaults: both(""), validate: valid},
+	"inlineStyleNonce":       {defaults: map[version.Version]any{version.V2: ""}, validate: valid},
+	"attributesToSettle":     {defaults: both([]string{"class", "style", "width", "height"}), validate: validAttributes},
+	"useTemplateFragments":   {defaults: map[version.Version]any{version.V1: false}, validate: valid},
+	"wsReconnectDelay":       {defaults: both(ReconnectFullJitter), validate: oneOf(ReconnectFullJitter)},
+	"wsBinaryType":           {defaults: both(BinaryTypeBlob), validate: oneOf(BinaryTypeBlob, BinaryTypeArrayBuffer)},
+	"disableSelector":        {defaults: both("[hx-disable], [data-hx-disable]"), validate: validSelector},
+	"withCredentials":        {defaults: both(false), validate: valid},
+	"timeout":                {defaults: both(0), validate: nonNegative},
+	"scrollBehavior": {
+		defaults: map[version.Version]any{version.V1: ScrollBehaviorSmooth, version.V2: ScrollBehaviorInstant},
+		validate: validScrollBehavior,
+	},
+	"defaultFocusScroll":    {defaults: both(false), validate: valid},
+	"getCacheBusterParam":   {defaults: both(false), validate: valid},
+	"globalViewTransitions": {defaults: both(false), validate: valid},
+	"methodsThatUseUrlParams": {
+		defaults: map[version.Version]any{version.V1: []HTTPMethod{MethodGet}, version.V2: []HTTPMethod{MethodGet, MethodDelete}},
+		validate: validMethods,
+	},
+	"selfRequestsOnly":      {defaults: map[version.Version]any{version.V1: false, version.V2: true}, validate: valid},
+	"ignoreTitle":           {defaults: both(false), validate: valid},
+	"scrollIntoViewOnBoost": {defaults: both(true), validate: valid},
+	"triggerSpecsCache":     {defaults: both(nil), validate: valid},
+	"disableInheritance":    {defaults: map[version.Version]any{version.V2: false}, validate: valid},
+	"responseHandling":      {defaults: map[version.Version]any{version.V2: DefaultResponseHandling()}, validate: validResponseHandling},
+	"allowNestedOobSwaps":   {defaults: map[version.Version]any{version.V2: true}, validate: valid},
+}
+
+// milliseconds converts a duration to the whole milliseconds htmx expects.
+func milliseconds(d time.Duration) int {
+	return int(d / time.Millisecond)
+}
+
+// equal checks if two config values render the same JSON.
+func equal(a, b any) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJSON) == string(bJSON)
+}
+
+// invalid returns an error for an invalid config value.
+func invalid(name string, value any, reason string) error {
+	return fmt.Errorf("%w: config %s %q %s", ErrInvalid, name, fmt.Sprint(value), reason)
+}
+
+// valid accepts any value, for keys whose setters only allow valid values.
+func valid(string, any, version.Version) error {
+	return nil
+}
+
+func nonNegative(name string, value any, _ version.Version) error {
+	if n, _ := value.(int); n < 0 {
+		return invalid(name, value, "must not be negative")
+	}
+	return nil
+}
+
+func validClass(name string, value any, _ version.Version) error {
+	class, _ := value.(string)
+	if class == "" || strings.ContainsAny(class, " \t\n.") {
+		return invalid(name, value, "is not a class name")
+	}
+	return nil
+}
+
+func validAttributes(name string, value any, _ version.Version) error {
+	attrs, _ := value.([]string)
+	for _, attr := range attrs {
+		if attr == "" || strings.ContainsAny(attr, " \t\n\"'=<>/") {
+			return invalid(name, attr, "is not an attribute name")
+		}
+	}
+	return nil
+}
+
+func validSelector(name string, value any, _ version.Version) error {
+	selector, _ := value.(string)
+	if _, err := cascadia.ParseGroup(selector); err != nil {
+		return invalid(name, value, "is not a CSS selector")
+	}
+	return nil
+}
+
+func validStrategy(name string, value any, v version.Version) error {
+	strategy, _ := value.(swap.Strategy)
+	if _, err := swap.ParseStrategy(string(strategy)); err != nil {
+		return invalid(name, value, "is not a swap strategy")
+	}
+	if strategy == swap.TextContent && v < version.V2 {
+		return version.Unsupported(fmt.Sprintf("config %s %q", name, strategy), v)
+	}
+	return nil
+}
+
+func validScrollBehavior(name string, value any, v version.Version) error {
+	behavior, _ := value.(ScrollBehavior)
+	switch behavior {
+	case ScrollBehaviorAuto, ScrollBehaviorSmooth:
+		return nil
+	case ScrollBehaviorInstant:
+		if v < version.V2 {
+			return version.Unsupported(fmt.Sprintf("config %s %q", name, behavior), v)
+		}
+		return nil
+	}
+	return invalid(name, value, "is not a scroll behavior")
+}
+
+func validMethods(name string, value any, _ version.Version) error {
+	methods, _ := value.([]HTTPMethod)
+	for _, method := range methods {
+		switch method {
+		case MethodGet, MethodPost, MethodPut, MethodDelete, MethodPatch, MethodHead, MethodOptions:
+		default:
+			return invalid(name, method, "is not an HTTP method")
+		}
+	}
+	return nil
+}
+
+// oneOf accepts only the given values.
+func oneOf[T comparable](allowed ...T) func(string, any, version.Version) error {
+	return func(name string, value any, _ version.Version) error {
+		for _, a := range allowed {
+			if value == any(a) {
+				return nil
+			}
+		}
+		return invalid(name, value, fmt.Sprintf("is not one of %v", allowed))
+	}
+}
@@ -0,0 +1,174 @@
+package hxconfig_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	"github.com/will-wow/typed-htmx-go/htmx/version"
+)
+
+func ExampleBuilder_BuildFor() {
+	config := hxconfig.New().
+		SelfRequestsOnly(false).
+		DefaultSwapStyle(swap.InnerHTML).
+		Timeout(2 * time.Second)
+
+	v1, _ := config.JSON(version.V1)
+	v2, _ := config.JSON(version.V2)
+	fmt.Println(v1)
+	fmt.Println(v2)
+	// Output:
+	// {"timeout":2000}
+	// {"selfRequestsOnly":false,"timeout":2000}
+}
+
+func ExampleBuilder_Templ() {
+	config := hxconfig.New().IncludeIndicatorStyles(false)
+	_ = config.Templ(version.V2).Render(context.Background(), os.Stdout)
+	// Output: <meta name="htmx-config" content="{&#34;includeIndicatorStyles&#34;:false}">
+}
+
+func ExampleBuilder_Gomponents() {
+	config := hxconfig.New().WSBinaryType(hxconfig.BinaryTypeArrayBuffer)
+	_ = config.Gomponents(version.V2).Render(os.Stdout)
+	// Output: <meta name="htmx-config" content="{&#34;wsBinaryType&#34;:&#34;arraybuffer&#34;}">
+}
+
+func TestBuilder_BuildFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *hxconfig.Builder
+		version version.Version
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "empty",
+			config:  hxconfig.New(),
+			version: version.V1,
+			want:    "{}",
+		},
+		{
+			name:    "all defaults",
+			config:  hxconfig.New().HistoryCacheSize(10).DefaultSettleDelay(20 * time.Millisecond).AttributesToSettle([]string{"class", "style", "width", "height"}).TriggerSpecsCache(false),
+			version: version.V2,
+			want:    "{}",
+		},
+		{
+			name:    "v2 defaults differ from v1",
+			config:  hxconfig.New().ScrollBehavior(hxconfig.ScrollBehaviorInstant).MethodsThatUseUrlParams([]hxconfig.HTTPMethod{hxconfig.MethodGet, hxconfig.MethodDelete}),
+			version: version.V2,
+			want:    "{}",
+		},
+		{
+			name:    "v1 keeps changed defaults",
+			config:  hxconfig.New().ScrollBehavior(hxconfig.ScrollBehaviorAuto).MethodsThatUseUrlParams([]hxconfig.HTTPMethod{hxconfig.MethodGet, hxconfig.MethodDelete}),
+			version: version.V1,
+			want:    `{"methodsThatUseUrlParams":["get","delete"],"scrollBehavior":"auto"}`,
+		},
+		{
+			name:    "trigger specs cache",
+			config:  hxconfig.New().TriggerSpecsCache(true),
+			version: version.V1,
+			want:    `{"triggerSpecsCache":{}}`,
+		},
+		{
+			name:    "v2 only key",
+			config:  hxconfig.New().InlineStyleNonce("abc").AllowNestedOobSwaps(false),
+			version: version.V1,
+			want:    "{}",
+			wantErr: version.ErrUnsupported,
+		},
+		{
+			name:    "v2 only swap",
+			config:  hxconfig.New().DefaultSwapStyle(swap.TextContent),
+			version: version.V1,
+			want:    "{}",
+			wantErr: version.ErrUnsupported,
+		},
+		{
+			name:    "invalid class",
+			config:  hxconfig.New().IndicatorClass(".loading").Timeout(time.Second),
+			version: version.V2,
+			want:    `{"timeout":1000}`,
+			wantErr: hxconfig.ErrInvalid,
+		},
+		{
+			name:    "negative duration",
+			config:  hxconfig.New().DefaultSwapDelay(-time.Second),
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+		{
+			name:    "invalid selector",
+			config:  hxconfig.New().DisableSelector("[hx-disable"),
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+		{
+			name:    "invalid enum",
+			config:  hxconfig.New().WSBinaryType("text").ScrollBehavior("fast").MethodsThatUseUrlParams([]hxconfig.HTTPMethod{"GET"}),
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+		{
+			name:    "invalid attribute",
+			config:  hxconfig.New().AttributesToSettle([]string{"class", "data value"}),
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.JSON(tt.version)
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildFor_errors(t *testing.T) {
+	_, err := hxconfig.New().
+		WSBinaryType("text").
+		SettlingClass("").
+		UseTemplateFragments(true).
+		BuildFor(version.V2)
+
+	want := []string{
+		"htmx: not supported by this version: config useTemplateFragments is not supported by htmx 2.x",
+		"hxconfig: invalid value: config settlingClass \"\" is not a class name",
+		"hxconfig: invalid value: config wsBinaryType \"text\" is not one of [blob arraybuffer]",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("got %v, want %s", err, strings.Join(want, "\n"))
+	}
+}
+
+func TestBuilder_Gomponents_error(t *testing.T) {
+	var b strings.Builder
+	err := hxconfig.New().Timeout(-time.Second).AddedClass("added").Gomponents(version.V1).Render(&b)
+	if want := `<meta name="htmx-config" content="{&#34;addedClass&#34;:&#34;added&#34;}">`; b.String() != want {
+		t.Errorf("got %s, want %s", b.String(), want)
+	}
+	if !errors.Is(err, hxconfig.ErrInvalid) {
+		t.Errorf("got error %v, want invalid", err)
+	}
+}
@@ -0,0 +1,41 @@
+package hxconfig
+
+import (
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+	"github.com/maragudk/gomponents/html"
+
+	"github.com/will-wow/typed-htmx-go/htmx/version"
+)
+
+// Templ renders the config for a version of htmx as a `<meta name="htmx-config">` element, for the head of a templ layout.
+//
+//	@hxconfig.New().DefaultSwapStyle(swap.OuterHTML).Templ(hx.Version())
+//
+// Invalid and unsupported keys are left out of the element, and returned as an error after it's rendered.
+func (b *Builder) Templ(v version.Version) templ.Component {
+	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		return b.Gomponents(v).Render(w)
+	})
+}
+
+// Gomponents renders the config for a version of htmx as a `<meta name="htmx-config">` element, for the head of a gomponents layout.
+//
+//	Head(hxconfig.New().DefaultSwapStyle(swap.OuterHTML).Gomponents(hx.Version()))
+//
+// Invalid and unsupported keys are left out of the element, and returned as an error after it's rendered.
+func (b *Builder) Gomponents(v version.Version) g.Node {
+	return g.NodeFunc(func(w io.Writer) error {
+		content, configErr := b.JSON(v)
+		if content == "" {
+			return configErr
+		}
+		if err := html.Meta(html.Name("htmx-config"), html.Content(content)).Render(w); err != nil {
+			return err
+		}
+		return configErr
+	})
+}
@@ -0,0 +1,198 @@
+package hxconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/will-wow/typed-htmx-go/htmx/internal/target"
+	"github.com/will-wow/typed-htmx-go/htmx/version"
+)
+
+// A Code matches HTTP response codes for a [ResponseRule].
+// The codes from the response-targets extension can be used directly, like responsetargets.Status(http.StatusUnprocessableEntity),
+// responsetargets.Wildcard(4) for all 4xx codes, or responsetargets.Error for all 4xx and 5xx codes.
+// For anything else, use a [Pattern].
+type Code interface {
+	String() string
+}
+
+// A Pattern is a regular expression that matches HTTP response codes, as used by htmx.
+type Pattern string
+
+var _ Code = Pattern("")
+
+func (p Pattern) String() string {
+	return string(p)
+}
+
+const (
+	Success Pattern = "[23].." // Success matches 2xx and 3xx response codes.
+	Any     Pattern = "..."    // Any matches every response code.
+)
+
+// A ResponseRule decides how htmx 2 handles responses with matching status codes.
+// By default, matching responses are not swapped.
+//
+// [HTMX Docs]
+//
+// [HTMX Docs]: https://htmx.org/docs/#response-handling
+type ResponseRule struct {
+	code        Code
+	swap        bool
+	isError     bool
+	ignoreTitle bool
+	selector    string
+	target      target.Selector
+}
+
+// Response starts a rule for responses with a matching status code.
+func Response(code Code) *ResponseRule {
+	return &ResponseRule{
+		code:        code,
+		swap:        false,
+		isError:     false,
+		ignoreTitle: false,
+		selector:    "",
+		target:      "",
+	}
+}
+
+// Swap swaps matching responses into the page.
+func (r *ResponseRule) Swap() *ResponseRule {
+	r.swap = true
+	return r
+}
+
+// Error treats matching responses as errors, which fires the htmx:responseError event.
+func (r *ResponseRule) Error() *ResponseRule {
+	r.isError = true
+	return r
+}
+
+// IgnoreTitle keeps htmx from updating the document title from a title tag in matching responses.
+func (r *ResponseRule) IgnoreTitle() *ResponseRule {
+	r.ignoreTitle = true
+	return r
+}
+
+// Select swaps only the part of matching responses that matches a CSS selector, like hx-select.
+func (r *ResponseRule) Select(selector string) *ResponseRule {
+	r.selector = selector
+	return r
+}
+
+// Target swaps matching responses into a different element, like hx-target. It takes an htmx.TargetSelector.
+func (r *ResponseRule) Target(selector target.Selector) *ResponseRule {
+	r.target = selector
+	return r
+}
+
+// MarshalJSON renders the rule as an entry in the responseHandling config.
+func (r *ResponseRule) MarshalJSON() ([]byte, error) {
+	pattern, err := codePattern(r.code)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Code        string `json:"code"`
+		Swap        bool   `json:"swap"`
+		Error       bool   `json:"error,omitempty"`
+		IgnoreTitle bool   `json:"ignoreTitle,omitempty"`
+		Select      string `json:"select,omitempty"`
+		Target      string `json:"target,omitempty"`
+	}{
+		Code:        pattern,
+		Swap:        r.swap,
+		Error:       r.isError,
+		IgnoreTitle: r.ignoreTitle,
+		Select:      r.selector,
+		Target:      string(r.target),
+	})
+}
+
+// DefaultResponseHandling returns the rules htmx 2 uses by default.
+// 204 No Content responses and all other codes besides 2xx and 3xx are not swapped, and 4xx and 5xx responses are errors.
+//
+// Custom rules can be added before the defaults:
+//
+//	hxconfig.New().ResponseHandling(append(
+//		[]*hxconfig.ResponseRule{hxconfig.Response(responsetargets.Status(http.StatusUnprocessableEntity)).Swap()},
+//		hxconfig.DefaultResponseHandling()...,
+//	)...)
+func DefaultResponseHandling() []*ResponseRule {
+	return []*ResponseRule{
+		Response(Pattern("204")),
+		Response(Success).Swap(),
+		Response(Pattern("[45]..")).Error(),
+		Response(Any),
+	}
+}
+
+// ResponseHandling sets the rules htmx 2 uses to handle responses by status code, replacing the defaults.
+// htmx uses the first rule that matches the response code, so a rule that can't match any codes the earlier rules don't is reported as invalid.
+// Requires htmx 2.x.
+func (b *Builder) ResponseHandling(rules ...*ResponseRule) *Builder {
+	b.config["responseHandling"] = rules
+	return b
+}
+
+// codePattern converts a response code to the regular expression htmx matches against the status code.
+func codePattern(code Code) (string, error) {
+	if code == nil {
+		return "", fmt.Errorf("%w: response code is missing", ErrInvalid)
+	}
+	if pattern, ok := code.(Pattern); ok {
+		if _, err := regexp.Compile(string(pattern)); err != nil {
+			return "", fmt.Errorf("%w: response code %q is not a regular expression", ErrInvalid, pattern)
+		}
+		return string(pattern), nil
+	}
+
+	s := code.String()
+	if s == "error" {
+		return "[45]..", nil
+	}
+	digits := strings.TrimRight(s, "*x")
+	if len(digits) > 3 || (len(digits) < 3 && len(digits) != len(s)-1) || (len(digits) == 3 && digits != s) ||
+		strings.Trim(digits, "0123456789") != "" {
+		return "", fmt.Errorf("%w: response code %q", ErrInvalid, s)
+	}
+	return digits + strings.Repeat(".", 3-len(digits)), nil
+}
+
+// validResponseHandling checks that every rule can match a status code that the rules before it don't.
+func validResponseHandling(name string, value any, _ version.Version) error {
+	rules, _ := value.([]*ResponseRule)
+	var matched [600]bool
+	for _, rule := range rules {
+		if rule == nil {
+			return invalid(name, "nil", "is not a response rule")
+		}
+		pattern, err := codePattern(rule.code)
+		if err != nil {
+			return err
+		}
+		re := regexp.MustCompile(pattern)
+
+		reachable, matches := false, false
+		for status := 100; status < len(matched); status++ {
+			if !re.MatchString(fmt.Sprint(status)) {
+				continue
+			}
+			matches = true
+			if !matched[status] {
+				reachable = true
+				matched[status] = true
+			}
+		}
+		if !matches {
+			return invalid(name, pattern, "doesn't match any response codes")
+		}
+		if !reachable {
+			return invalid(name, pattern, "is unreachable, because earlier rules match every code it does")
+		}
+	}
+	return nil
+}
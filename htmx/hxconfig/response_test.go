@@ -0,0 +1,121 @@
+package hxconfig_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/responsetargets"
+	"github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+	"github.com/will-wow/typed-htmx-go/htmx/version"
+)
+
+// ExampleBuilder_ResponseHandling swaps the re-rendered form from a 422 validation error, like the click-to-edit example, without the response-targets extension.
+func ExampleBuilder_ResponseHandling() {
+	config := hxconfig.New().ResponseHandling(append(
+		[]*hxconfig.ResponseRule{
+			hxconfig.Response(responsetargets.Status(http.StatusUnprocessableEntity)).
+				Swap().
+				Error().
+				Target(htmx.TargetRelative(htmx.Closest, "form")),
+		},
+		hxconfig.DefaultResponseHandling()...,
+	)...)
+
+	json, _ := config.JSON(version.V2)
+	fmt.Println(json)
+	// Output: {"responseHandling":[{"code":"422","swap":true,"error":true,"target":"closest form"},{"code":"204","swap":false},{"code":"[23]..","swap":true},{"code":"[45]..","swap":false,"error":true},{"code":"...","swap":false}]}
+}
+
+func TestBuilder_ResponseHandling(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []*hxconfig.ResponseRule
+		version version.Version
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "defaults",
+			rules:   hxconfig.DefaultResponseHandling(),
+			version: version.V2,
+			want:    "{}",
+		},
+		{
+			name: "codes",
+			rules: []*hxconfig.ResponseRule{
+				hxconfig.Response(responsetargets.Wildcard(4, 0)).Swap().Select("#errors").IgnoreTitle(),
+				hxconfig.Response(responsetargets.WildcardX(5)).Swap().Target(htmx.TargetThis),
+				hxconfig.Response(responsetargets.Error),
+				hxconfig.Response(hxconfig.Success).Swap(),
+			},
+			version: version.V2,
+			want:    `{"responseHandling":[{"code":"40.","swap":true,"ignoreTitle":true,"select":"#errors"},{"code":"5..","swap":true,"target":"this"},{"code":"[45]..","swap":false},{"code":"[23]..","swap":true}]}`,
+		},
+		{
+			name:    "v1",
+			rules:   []*hxconfig.ResponseRule{hxconfig.Response(responsetargets.Status(http.StatusTeapot)).Swap()},
+			version: version.V1,
+			want:    "{}",
+			wantErr: version.ErrUnsupported,
+		},
+		{
+			name: "unreachable rule",
+			rules: []*hxconfig.ResponseRule{
+				hxconfig.Response(responsetargets.Error).Error(),
+				hxconfig.Response(responsetargets.Status(http.StatusUnprocessableEntity)).Swap(),
+			},
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+		{
+			name: "rule covered by earlier rules",
+			rules: []*hxconfig.ResponseRule{
+				hxconfig.Response(responsetargets.Wildcard(4)),
+				hxconfig.Response(responsetargets.Wildcard(5)),
+				hxconfig.Response(responsetargets.Error).Swap(),
+			},
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+		{
+			name:    "pattern without codes",
+			rules:   []*hxconfig.ResponseRule{hxconfig.Response(hxconfig.Pattern("9.."))},
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+		{
+			name:    "invalid pattern",
+			rules:   []*hxconfig.ResponseRule{hxconfig.Response(hxconfig.Pattern("[4"))},
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+		{
+			name:    "invalid code",
+			rules:   []*hxconfig.ResponseRule{hxconfig.Response(responsetargets.Wildcard(4, 2, 2))},
+			version: version.V2,
+			want:    "{}",
+			wantErr: hxconfig.ErrInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hxconfig.New().ResponseHandling(tt.rules...).JSON(tt.version)
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
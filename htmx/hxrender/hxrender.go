@@ -0,0 +1,62 @@
+// package hxrender renders either a full page or just a fragment, depending on how htmx made the request.
+//
+// Pages that are linked with hx-boost, restored from history after a cache miss, or loaded directly by the browser need the full layout, while targeted htmx requests only need the content.
+// Handlers can render the same content either way, by passing a layout function:
+//
+//	func page(w http.ResponseWriter, r *http.Request) {
+//		w.Header().Add("Vary", "HX-Request")
+//		hxrender.Templ(r, hxrender.TemplChildren(layout.Wrapper("Contacts")), contacts()).Render(r.Context(), w)
+//	}
+//
+// See [hxreq.Headers.FullPage] for the rules used to pick the full page.
+package hxrender
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+
+	"github.com/will-wow/typed-htmx-go/htmx/hxreq"
+)
+
+// A TemplLayout wraps content in a full page.
+type TemplLayout func(content templ.Component) templ.Component
+
+// TemplChildren adapts a templ layout that renders its content with `{ children... }` into a [TemplLayout].
+//
+//	hxrender.TemplChildren(layout.Wrapper("Contacts"))
+func TemplChildren(layout templ.Component) TemplLayout {
+	return func(content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return layout.Render(templ.WithChildren(ctx, content), w)
+		})
+	}
+}
+
+// Templ returns the content wrapped in the layout if the request needs a full page, or just the content for a fragment request.
+func Templ(r *http.Request, layout TemplLayout, content templ.Component) templ.Component {
+	if hxreq.Parse(r).FullPage() {
+		return layout(content)
+	}
+	return content
+}
+
+// A GomponentsLayout wraps content in a full page.
+//
+// Layouts that take children can be adapted with a closure:
+//
+//	func(content g.Node) g.Node {
+//		return layout.Wrapper("Contacts", content)
+//	}
+type GomponentsLayout func(content g.Node) g.Node
+
+// Gomponents returns the content wrapped in the layout if the request needs a full page, or just the content for a fragment request.
+func Gomponents(r *http.Request, layout GomponentsLayout, content g.Node) g.Node {
+	if hxreq.Parse(r).FullPage() {
+		return layout(content)
+	}
+	return content
+}
@@ -0,0 +1,68 @@
+package hxrender_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+	"github.com/maragudk/gomponents/html"
+
+	"github.com/will-wow/typed-htmx-go/htmx/hxrender"
+)
+
+// wrapper is a templ layout that renders its children, like a `{ children... }` block.
+func wrapper(title string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, _ = fmt.Fprintf(w, "<html><title>%s</title><body>", title)
+		if err := templ.GetChildren(ctx).Render(ctx, w); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "</body></html>")
+		return err
+	})
+}
+
+func ExampleTempl() {
+	layout := hxrender.TemplChildren(wrapper("Contacts"))
+	content := templ.Raw(`<ul id="contacts"></ul>`)
+
+	r := httptest.NewRequest(http.MethodGet, "/contacts", nil)
+	_ = hxrender.Templ(r, layout, content).Render(context.Background(), os.Stdout)
+	fmt.Println()
+
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Target", "contacts")
+	_ = hxrender.Templ(r, layout, content).Render(context.Background(), os.Stdout)
+	fmt.Println()
+
+	r.Header.Set("HX-History-Restore-Request", "true")
+	_ = hxrender.Templ(r, layout, content).Render(context.Background(), os.Stdout)
+	// Output:
+	// <html><title>Contacts</title><body><ul id="contacts"></ul></body></html>
+	// <ul id="contacts"></ul>
+	// <html><title>Contacts</title><body><ul id="contacts"></ul></body></html>
+}
+
+func ExampleGomponents() {
+	layout := func(content g.Node) g.Node {
+		return html.Body(content)
+	}
+	content := html.Ul(html.ID("contacts"))
+
+	r := httptest.NewRequest(http.MethodGet, "/contacts", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Boosted", "true")
+	_ = hxrender.Gomponents(r, layout, content).Render(os.Stdout)
+	fmt.Println()
+
+	r.Header.Set("HX-Target", "contacts")
+	_ = hxrender.Gomponents(r, layout, content).Render(os.Stdout)
+	// Output:
+	// <body><ul id="contacts"></ul></body>
+	// <ul id="contacts"></ul>
+}
@@ -0,0 +1,111 @@
+// package hxreq parses the HX-* request headers that htmx sends with every AJAX request, so handlers and components can tell how a request was made.
+//
+// [Request Headers]
+//
+// [Request Headers]: https://htmx.org/reference/#request_headers
+package hxreq
+
+import (
+	"context"
+	"net/http"
+)
+
+// A Header is the name of an htmx request header.
+type Header string
+
+const (
+	Request               Header = "HX-Request"                 // always “true”
+	Boosted               Header = "HX-Boosted"                 // indicates that the request is via an element using hx-boost
+	CurrentURL            Header = "HX-Current-URL"             // the current URL of the browser
+	HistoryRestoreRequest Header = "HX-History-Restore-Request" // “true” if the request is for history restoration after a miss in the local history cache
+	Prompt                Header = "HX-Prompt"                  // the user response to an hx-prompt
+	Target                Header = "HX-Target"                  // the id of the target element if it exists
+	TriggerName           Header = "HX-Trigger-Name"            // the name of the triggered element if it exists
+	Trigger               Header = "HX-Trigger"                 // the id of the triggered element if it exists
+)
+
+// Headers holds the parsed values of the htmx request headers.
+type Headers struct {
+	Request               bool   // the request was made by htmx
+	Boosted               bool   // the request is via an element using hx-boost
+	CurrentURL            string // the current URL of the browser
+	HistoryRestoreRequest bool   // the request is for history restoration after a miss in the local history cache
+	Prompt                string // the user response to an hx-prompt
+	Target                string // the id of the target element, if it exists
+	TriggerName           string // the name of the triggered element, if it exists
+	Trigger               string // the id of the triggered element, if it exists
+}
+
+// Parse reads the htmx request headers from an HTTP request.
+// Requests that were not made by htmx return a Headers with Request set to false.
+func Parse(r *http.Request) Headers {
+	return ParseHeader(r.Header)
+}
+
+// ParseHeader reads the htmx request headers from a raw http.Header.
+func ParseHeader(h http.Header) Headers {
+	return Headers{
+		Request:               isTrue(h, Request),
+		Boosted:               isTrue(h, Boosted),
+		CurrentURL:            h.Get(string(CurrentURL)),
+		HistoryRestoreRequest: isTrue(h, HistoryRestoreRequest),
+		Prompt:                h.Get(string(Prompt)),
+		Target:                h.Get(string(Target)),
+		TriggerName:           h.Get(string(TriggerName)),
+		Trigger:               h.Get(string(Trigger)),
+	}
+}
+
+// isTrue checks if a boolean htmx header is set to “true”.
+func isTrue(h http.Header, header Header) bool {
+	return h.Get(string(header)) == "true"
+}
+
+// contextKey is the key used to store the parsed Headers in a request context.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the parsed htmx headers.
+func NewContext(ctx context.Context, headers Headers) context.Context {
+	return context.WithValue(ctx, contextKey{}, headers)
+}
+
+// FromContext returns the htmx headers stored in ctx by [Middleware] or [NewContext].
+// If no headers were stored, it returns an empty Headers, which is treated as a non-htmx request.
+func FromContext(ctx context.Context) Headers {
+	headers, _ := ctx.Value(contextKey{}).(Headers)
+	return headers
+}
+
+// Middleware parses the htmx request headers and stores them in the request context, so that they can be read with [FromContext] by any handler or component rendered with that context.
+//
+//	mux := http.NewServeMux()
+//	http.ListenAndServe(":8080", hxreq.Middleware(mux))
+//
+// In a templ component:
+//
+//	if hxreq.FromContext(ctx).Boosted {
+//		...
+//	}
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := NewContext(r.Context(), Parse(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// FullPage reports whether a response to the request should include the full page layout, rather than just a fragment.
+//
+// This is true for requests not made by htmx, for history restoration requests (which replace the whole body after a cache miss), and for boosted requests that don't target a specific element.
+// Only htmx requests that target an element with an id should be answered with a fragment.
+func (h Headers) FullPage() bool {
+	switch {
+	case !h.Request:
+		return true
+	case h.HistoryRestoreRequest:
+		return true
+	case h.Boosted && h.Target == "":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,116 @@
+package hxreq_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/htmx/hxreq"
+)
+
+func ExampleParse() {
+	r := httptest.NewRequest(http.MethodGet, "/contacts", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Boosted", "true")
+	r.Header.Set("HX-Target", "content")
+	r.Header.Set("HX-Current-URL", "http://localhost/")
+
+	headers := hxreq.Parse(r)
+	fmt.Printf("%+v\n", headers)
+	// Output: {Request:true Boosted:true CurrentURL:http://localhost/ HistoryRestoreRequest:false Prompt: Target:content TriggerName: Trigger:}
+}
+
+func ExampleParse_nonHTMX() {
+	r := httptest.NewRequest(http.MethodGet, "/contacts", nil)
+
+	fmt.Println(hxreq.Parse(r).Request)
+	// Output: false
+}
+
+func ExampleMiddleware() {
+	handler := hxreq.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers := hxreq.FromContext(r.Context())
+		fmt.Println(headers.Trigger, headers.TriggerName, headers.Prompt)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/delete", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Trigger", "delete-btn")
+	r.Header.Set("HX-Trigger-Name", "delete")
+	r.Header.Set("HX-Prompt", "yes")
+
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	// Output: delete-btn delete yes
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("empty context", func(t *testing.T) {
+		got := hxreq.FromContext(context.Background())
+		if got != (hxreq.Headers{}) {
+			t.Errorf("got %+v, want empty headers", got)
+		}
+	})
+
+	t.Run("history restore", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("HX-History-Restore-Request", "true")
+		ctx := hxreq.NewContext(context.Background(), hxreq.ParseHeader(h))
+
+		if got := hxreq.FromContext(ctx); !got.HistoryRestoreRequest {
+			t.Errorf("got %+v, want HistoryRestoreRequest", got)
+		}
+	})
+}
+
+func TestHeadersFullPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{
+			name:    "non-htmx request",
+			headers: map[string]string{},
+			want:    true,
+		},
+		{
+			name:    "targeted request",
+			headers: map[string]string{"HX-Request": "true", "HX-Target": "content"},
+			want:    false,
+		},
+		{
+			name:    "untargeted request",
+			headers: map[string]string{"HX-Request": "true"},
+			want:    false,
+		},
+		{
+			name:    "history restore",
+			headers: map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true", "HX-Target": "content"},
+			want:    true,
+		},
+		{
+			name:    "boosted without target",
+			headers: map[string]string{"HX-Request": "true", "HX-Boosted": "true"},
+			want:    true,
+		},
+		{
+			name:    "boosted with target",
+			headers: map[string]string{"HX-Request": "true", "HX-Boosted": "true", "HX-Target": "content"},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for name, value := range tt.headers {
+				h.Set(name, value)
+			}
+			if got := hxreq.ParseHeader(h).FullPage(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package hxres
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+)
+
+// An Event is a typed definition of a custom client-side event, with a detail payload of type T.
+//
+// Define an event once, then use it both to trigger the event from a response header, and to listen for it with hx-trigger:
+//
+//	type Toast struct {
+//		Message string `json:"message"`
+//	}
+//
+//	var ShowToast = hxres.NewEvent[Toast]("showToast")
+//
+//	// In the handler:
+//	hxres.New().TriggerDetail(ShowToast.With(Toast{Message: "Saved!"})).Apply(w)
+//
+//	// In the component:
+//	<div { hx.TriggerExtended(ShowToast.On().From("body"))... }>
+//
+// The detail payload is marshaled to JSON, and is available to client-side listeners as event.detail.
+type Event[T any] struct {
+	name trigger.TriggerEvent
+}
+
+// NewEvent defines a new event with the given name and a detail payload of type T.
+func NewEvent[T any](name trigger.TriggerEvent) Event[T] {
+	return Event[T]{name: name}
+}
+
+// Name returns the event name, for use with [Builder.Trigger] or [htmx.HX.Trigger].
+func (e Event[T]) Name() trigger.TriggerEvent {
+	return e.name
+}
+
+// On starts a [trigger.Event] builder chain to listen for the event with [htmx.HX.TriggerExtended].
+// Events triggered by response headers are dispatched on the element that made the request, and bubble up, so listeners elsewhere on the page usually need a From modifier.
+func (e Event[T]) On() *trigger.Event {
+	return trigger.On(e.name)
+}
+
+// With pairs the event with a detail payload, to be passed to [Builder.TriggerDetail].
+// The payload is marshaled right away. If that fails, the builder leaves the event out and returns the error from [Builder.Apply].
+func (e Event[T]) With(detail T) Detail {
+	encoded, err := json.Marshal(detail)
+	if err != nil {
+		err = fmt.Errorf("%w: %s detail: %w", ErrInvalid, e.name, err)
+	}
+	return Detail{name: e.name, detail: encoded, hasDetail: true, err: err}
+}
+
+// A Detail is an event paired with a detail payload, created by [Event.With].
+type Detail struct {
+	name      trigger.TriggerEvent
+	detail    json.RawMessage
+	hasDetail bool
+	err       error
+}
+
+// encodeTriggers encodes the value of a trigger header.
+// Events with no details are sent as a comma-separated list of names, and otherwise all events are merged into a single JSON object.
+func encodeTriggers(details []Detail) string {
+	if !hasDetails(details) {
+		names := make([]string, len(details))
+		for i, d := range details {
+			names[i] = string(d.name)
+		}
+		return strings.Join(names, ", ")
+	}
+
+	var b bytes.Buffer
+	_ = b.WriteByte('{')
+	for i, d := range details {
+		if i > 0 {
+			_ = b.WriteByte(',')
+		}
+		name, _ := json.Marshal(string(d.name))
+		_, _ = b.Write(name)
+		_ = b.WriteByte(':')
+		_, _ = b.Write(encodeDetail(d))
+	}
+	_ = b.WriteByte('}')
+	return b.String()
+}
+
+// hasDetails checks if any event has a detail payload.
+func hasDetails(details []Detail) bool {
+	for _, d := range details {
+		if d.hasDetail {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeDetail returns the JSON for a single detail payload, or null for an event without one.
+func encodeDetail(d Detail) []byte {
+	if !d.hasDetail {
+		return []byte("null")
+	}
+	return d.detail
+}
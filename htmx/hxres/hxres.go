@@ -0,0 +1,353 @@
+// package hxres builds the HX-* response headers that control how htmx handles a response.
+//
+// The builder reuses the same typed values as the attribute builders, so a [swap.Builder] or [htmx.TargetSelector] can drive either an attribute or a header.
+//
+//	hxres.New().
+//		Retarget("#errors").
+//		Reswap(swap.InnerHTML).
+//		Apply(w)
+//
+// See the [Response Headers] reference.
+//
+// [Response Headers]: https://htmx.org/reference/#response_headers
+package hxres
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/internal/util"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+)
+
+// A Header is the name of an htmx response header.
+type Header string
+
+const (
+	Location           Header = "HX-Location"             // allows you to do a client-side redirect that does not do a full page reload
+	PushURL            Header = "HX-Push-Url"             // pushes a new url into the history stack
+	Redirect           Header = "HX-Redirect"             // can be used to do a client-side redirect to a new location
+	Refresh            Header = "HX-Refresh"              // if set to “true” the client-side will do a full refresh of the page
+	ReplaceURL         Header = "HX-Replace-Url"          // replaces the current URL in the location bar
+	Reswap             Header = "HX-Reswap"               // allows you to specify how the response will be swapped
+	Retarget           Header = "HX-Retarget"             // a CSS selector that updates the target of the content update to a different element on the page
+	Reselect           Header = "HX-Reselect"             // a CSS selector that allows you to choose which part of the response is used to be swapped in
+	Trigger            Header = "HX-Trigger"              // allows you to trigger client-side events
+	TriggerAfterSettle Header = "HX-Trigger-After-Settle" // allows you to trigger client-side events after the settle step
+	TriggerAfterSwap   Header = "HX-Trigger-After-Swap"   // allows you to trigger client-side events after the swap step
+)
+
+// StatusStopPolling is a non-standard HTTP status code. Responding to a polling request with it will cause htmx to stop polling.
+//
+// See [Load Polling].
+//
+// [Load Polling]: https://htmx.org/docs/#load_polling
+const StatusStopPolling = 286
+
+// ErrInvalid is returned by [Builder.Apply] and [Builder.Err] for a header value that can't be encoded, like an event detail that can't be marshaled to JSON.
+var ErrInvalid = errors.New("hxres: invalid header value")
+
+// A Builder collects htmx response headers, to be applied to an [http.ResponseWriter] with [Builder.Apply].
+type Builder struct {
+	headers  map[Header]string
+	triggers map[Header][]Detail
+	errs     []error
+}
+
+// New starts a builder chain for setting htmx response headers.
+// Subsequent calls for the same header override previous values, except for the trigger headers, which accumulate events.
+func New() *Builder {
+	return &Builder{
+		headers:  map[Header]string{},
+		triggers: map[Header][]Detail{},
+		errs:     nil,
+	}
+}
+
+// Location allows you to do a client-side redirect that does not do a full page reload.
+//
+// Instead of changing the page’s location it will act like following a hx-boost link, creating a new history entry, issuing an ajax request to the value of the header and pushing the path into history.
+//
+// HTMX Header: [HX-Location]
+//
+// [HX-Location]: https://htmx.org/headers/hx-location/
+func (b *Builder) Location(path string, a ...any) *Builder {
+	if len(a) > 0 {
+		path = fmt.Sprintf(path, a...)
+	}
+	b.headers[Location] = path
+	return b
+}
+
+// PushURL controls whether the fetched URL is pushed into the browser location history.
+//
+// Passing false prevents the browser’s history from being updated, even if an hx-push-url attribute would otherwise push it.
+// To push a specific URL into history, use [Builder.PushURLPath].
+//
+// HTMX Header: [HX-Push-Url]
+//
+// [HX-Push-Url]: https://htmx.org/headers/hx-push-url/
+func (b *Builder) PushURL(on bool) *Builder {
+	b.headers[PushURL] = util.BoolToString(on)
+	return b
+}
+
+// PushURLPath pushes a new URL into the browser location history. This creates a new history entry, allowing navigation with the browser’s back and forward buttons.
+//
+// The URL may be relative or absolute, as per history.pushState(), and overrides an hx-push-url attribute.
+//
+// HTMX Header: [HX-Push-Url]
+//
+// [HX-Push-Url]: https://htmx.org/headers/hx-push-url/
+func (b *Builder) PushURLPath(url string, a ...any) *Builder {
+	if len(a) > 0 {
+		url = fmt.Sprintf(url, a...)
+	}
+	b.headers[PushURL] = url
+	return b
+}
+
+// Redirect can be used to do a client-side redirect to a new location, with a full page reload.
+//
+// HTMX Header: [HX-Redirect]
+//
+// [HX-Redirect]: https://htmx.org/headers/hx-redirect/
+func (b *Builder) Redirect(url string, a ...any) *Builder {
+	if len(a) > 0 {
+		url = fmt.Sprintf(url, a...)
+	}
+	b.headers[Redirect] = url
+	return b
+}
+
+// Refresh makes the client-side do a full refresh of the page.
+//
+// HTMX Header: [HX-Refresh]
+//
+// [HX-Refresh]: https://htmx.org/reference/#response_headers
+func (b *Builder) Refresh() *Builder {
+	b.headers[Refresh] = "true"
+	return b
+}
+
+// ReplaceURL controls whether the fetched URL replaces the current URL in the browser location bar.
+//
+// Passing false prevents the browser’s current URL from being updated, even if an hx-replace-url attribute would otherwise replace it.
+// To replace the location with a specific URL, use [Builder.ReplaceURLWith].
+//
+// HTMX Header: [HX-Replace-Url]
+//
+// [HX-Replace-Url]: https://htmx.org/headers/hx-replace-url/
+func (b *Builder) ReplaceURL(on bool) *Builder {
+	b.headers[ReplaceURL] = util.BoolToString(on)
+	return b
+}
+
+// ReplaceURLWith replaces the current URL in the browser location bar. This does not create a new history entry.
+//
+// The URL may be relative or absolute, as per history.replaceState(), and overrides an hx-replace-url attribute.
+//
+// HTMX Header: [HX-Replace-Url]
+//
+// [HX-Replace-Url]: https://htmx.org/headers/hx-replace-url/
+func (b *Builder) ReplaceURLWith(url string, a ...any) *Builder {
+	if len(a) > 0 {
+		url = fmt.Sprintf(url, a...)
+	}
+	b.headers[ReplaceURL] = url
+	return b
+}
+
+// Reswap allows you to specify how the response will be swapped, overriding the hx-swap attribute.
+//
+// For modifiers, see [Builder.ReswapExtended].
+//
+// HTMX Header: [HX-Reswap]
+//
+// [HX-Reswap]: https://htmx.org/reference/#response_headers
+func (b *Builder) Reswap(strategy swap.Strategy) *Builder {
+	b.headers[Reswap] = string(strategy)
+	return b
+}
+
+// ReswapExtended allows you to specify how the response will be swapped, with modifiers, overriding the hx-swap attribute.
+//
+// For documentation about modifiers, see [swap.Builder].
+//
+// HTMX Header: [HX-Reswap]
+//
+// [HX-Reswap]: https://htmx.org/reference/#response_headers
+func (b *Builder) ReswapExtended(swap *swap.Builder) *Builder {
+	b.headers[Reswap] = swap.String()
+	return b
+}
+
+// Retarget updates the target of the content update to a different element on the page, overriding the hx-target attribute.
+//
+// HTMX Header: [HX-Retarget]
+//
+// [HX-Retarget]: https://htmx.org/reference/#response_headers
+func (b *Builder) Retarget(extendedSelector htmx.TargetSelector) *Builder {
+	b.headers[Retarget] = string(extendedSelector)
+	return b
+}
+
+// Reselect allows you to choose which part of the response is used to be swapped in, overriding an existing hx-select on the triggering element.
+//
+// HTMX Header: [HX-Reselect]
+//
+// [HX-Reselect]: https://htmx.org/reference/#response_headers
+func (b *Builder) Reselect(selector htmx.StandardCSSSelector) *Builder {
+	b.headers[Reselect] = string(selector)
+	return b
+}
+
+// Trigger triggers client-side events as soon as the response is received.
+// Listen for them with [trigger.On] and an appropriate [trigger.Event.From] modifier, like `trigger.On("myEvent").From("body")`.
+//
+// To send events with a detail payload, use [Builder.TriggerDetail].
+//
+// HTMX Header: [HX-Trigger]
+//
+// [HX-Trigger]: https://htmx.org/headers/hx-trigger/
+func (b *Builder) Trigger(events ...trigger.TriggerEvent) *Builder {
+	return b.addTriggers(Trigger, events)
+}
+
+// TriggerAfterSettle triggers client-side events after the settle step.
+//
+// HTMX Header: [HX-Trigger-After-Settle]
+//
+// [HX-Trigger-After-Settle]: https://htmx.org/headers/hx-trigger/
+func (b *Builder) TriggerAfterSettle(events ...trigger.TriggerEvent) *Builder {
+	return b.addTriggers(TriggerAfterSettle, events)
+}
+
+// TriggerAfterSwap triggers client-side events after the swap step.
+//
+// HTMX Header: [HX-Trigger-After-Swap]
+//
+// [HX-Trigger-After-Swap]: https://htmx.org/headers/hx-trigger/
+func (b *Builder) TriggerAfterSwap(events ...trigger.TriggerEvent) *Builder {
+	return b.addTriggers(TriggerAfterSwap, events)
+}
+
+// TriggerDetail triggers client-side events with detail payloads as soon as the response is received.
+// Build each [Detail] from a typed [Event].
+//
+// HTMX Header: [HX-Trigger]
+//
+// [HX-Trigger]: https://htmx.org/headers/hx-trigger/
+func (b *Builder) TriggerDetail(details ...Detail) *Builder {
+	return b.addDetails(Trigger, details)
+}
+
+// TriggerDetailAfterSettle triggers client-side events with detail payloads after the settle step.
+//
+// HTMX Header: [HX-Trigger-After-Settle]
+//
+// [HX-Trigger-After-Settle]: https://htmx.org/headers/hx-trigger/
+func (b *Builder) TriggerDetailAfterSettle(details ...Detail) *Builder {
+	return b.addDetails(TriggerAfterSettle, details)
+}
+
+// TriggerDetailAfterSwap triggers client-side events with detail payloads after the swap step.
+//
+// HTMX Header: [HX-Trigger-After-Swap]
+//
+// [HX-Trigger-After-Swap]: https://htmx.org/headers/hx-trigger/
+func (b *Builder) TriggerDetailAfterSwap(details ...Detail) *Builder {
+	return b.addDetails(TriggerAfterSwap, details)
+}
+
+// addTriggers adds events without details to a trigger header.
+func (b *Builder) addTriggers(header Header, events []trigger.TriggerEvent) *Builder {
+	details := make([]Detail, len(events))
+	for i, event := range events {
+		details[i] = Detail{name: event, detail: nil, hasDetail: false, err: nil}
+	}
+	return b.addDetails(header, details)
+}
+
+// addDetails adds events to a trigger header.
+// An event that is already set keeps its position, and takes the new detail if one is passed.
+func (b *Builder) addDetails(header Header, details []Detail) *Builder {
+	for _, detail := range details {
+		if detail.err != nil {
+			b.errs = append(b.errs, detail.err)
+			continue
+		}
+		i := slices.IndexFunc(b.triggers[header], func(d Detail) bool {
+			return d.name == detail.name
+		})
+		switch {
+		case i == -1:
+			b.triggers[header] = append(b.triggers[header], detail)
+		case detail.hasDetail:
+			b.triggers[header][i] = detail
+		}
+	}
+	return b
+}
+
+// Header returns the built response headers.
+func (b *Builder) Header() http.Header {
+	h := http.Header{}
+	for header, value := range b.values() {
+		h.Set(string(header), value)
+	}
+	return h
+}
+
+// String returns the built headers in wire format, sorted by header name. Useful for debugging and tests.
+func (b *Builder) String() string {
+	values := b.values()
+
+	headers := make([]Header, 0, len(values))
+	for header := range values {
+		headers = append(headers, header)
+	}
+	slices.Sort(headers)
+
+	var sb strings.Builder
+	for _, header := range headers {
+		_, _ = fmt.Fprintf(&sb, "%s: %s\n", header, values[header])
+	}
+	return sb.String()
+}
+
+// values returns the value of every set header, with trigger events joined into a single value.
+func (b *Builder) values() map[Header]string {
+	values := make(map[Header]string, len(b.headers)+len(b.triggers))
+	for header, value := range b.headers {
+		values[header] = value
+	}
+	for header, events := range b.triggers {
+		if len(events) > 0 {
+			values[header] = encodeTriggers(events)
+		}
+	}
+	return values
+}
+
+// Err returns an error wrapping [ErrInvalid] for each value that couldn't be encoded, joined together.
+// Those values are left out of the headers, rather than being sent with the wrong payload.
+func (b *Builder) Err() error {
+	return errors.Join(b.errs...)
+}
+
+// Apply sets the built headers on the response.
+// It must be called before the response status or body is written.
+//
+// Values that couldn't be encoded are left out, and returned as an error like [Builder.Err].
+func (b *Builder) Apply(w http.ResponseWriter) error {
+	for header, values := range b.Header() {
+		w.Header()[header] = values
+	}
+	return b.Err()
+}
@@ -0,0 +1,213 @@
+package hxres_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/hxres"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+func ExampleBuilder_Apply() {
+	w := httptest.NewRecorder()
+
+	err := hxres.New().
+		Retarget(htmx.TargetRelative(htmx.Closest, "tr")).
+		Reswap(swap.OuterHTML).
+		Apply(w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Println(w.Header().Get("HX-Retarget"))
+	fmt.Println(w.Header().Get("HX-Reswap"))
+	// Output:
+	// closest tr
+	// outerHTML
+}
+
+func ExampleBuilder_Location() {
+	fmt.Print(hxres.New().Location("/contacts/%d", 1))
+	// Output: HX-Location: /contacts/1
+}
+
+func ExampleBuilder_PushURL() {
+	fmt.Print(hxres.New().PushURL(false))
+	// Output: HX-Push-Url: false
+}
+
+func ExampleBuilder_PushURLPath() {
+	fmt.Print(hxres.New().PushURLPath("/contacts/%d", 1))
+	// Output: HX-Push-Url: /contacts/1
+}
+
+func ExampleBuilder_Redirect() {
+	fmt.Print(hxres.New().Redirect("/login"))
+	// Output: HX-Redirect: /login
+}
+
+func ExampleBuilder_Refresh() {
+	fmt.Print(hxres.New().Refresh())
+	// Output: HX-Refresh: true
+}
+
+func ExampleBuilder_ReplaceURL() {
+	fmt.Print(hxres.New().ReplaceURL(false))
+	// Output: HX-Replace-Url: false
+}
+
+func ExampleBuilder_ReplaceURLWith() {
+	fmt.Print(hxres.New().ReplaceURLWith("/contacts/%d", 1))
+	// Output: HX-Replace-Url: /contacts/1
+}
+
+func ExampleBuilder_Reswap() {
+	fmt.Print(hxres.New().Reswap(swap.BeforeEnd))
+	// Output: HX-Reswap: beforeend
+}
+
+func ExampleBuilder_ReswapExtended() {
+	fmt.Print(hxres.New().ReswapExtended(
+		swap.New().Strategy(swap.OuterHTML).Settle(time.Second),
+	))
+	// Output: HX-Reswap: outerHTML settle:1s
+}
+
+func ExampleBuilder_Retarget() {
+	fmt.Print(hxres.New().Retarget(htmx.TargetThis))
+	// Output: HX-Retarget: this
+}
+
+func ExampleBuilder_Reselect() {
+	fmt.Print(hxres.New().Reselect("#info-details"))
+	// Output: HX-Reselect: #info-details
+}
+
+func ExampleBuilder_Trigger() {
+	fmt.Print(hxres.New().Trigger("done", "refresh").Trigger("done"))
+	// Output: HX-Trigger: done, refresh
+}
+
+func ExampleBuilder_TriggerAfterSettle() {
+	fmt.Print(hxres.New().TriggerAfterSettle("settled"))
+	// Output: HX-Trigger-After-Settle: settled
+}
+
+func ExampleBuilder_TriggerAfterSwap() {
+	fmt.Print(hxres.New().TriggerAfterSwap("swapped"))
+	// Output: HX-Trigger-After-Swap: swapped
+}
+
+func ExampleStatusStopPolling() {
+	w := httptest.NewRecorder()
+	w.WriteHeader(hxres.StatusStopPolling)
+
+	fmt.Println(w.Code)
+	// Output: 286
+}
+
+type toast struct {
+	Message string `json:"message"`
+	Level   string `json:"level"`
+}
+
+var showToast = hxres.NewEvent[toast]("showToast")
+
+var itemCount = hxres.NewEvent[int]("itemCount")
+
+func ExampleEvent() {
+	res := hxres.New().
+		TriggerDetail(showToast.With(toast{Message: "Saved!", Level: "info"}))
+
+	fmt.Print(res)
+	fmt.Println(showToast.On().From("body"))
+	// Output:
+	// HX-Trigger: {"showToast":{"message":"Saved!","level":"info"}}
+	// showToast from:(body)
+}
+
+func ExampleBuilder_TriggerDetail() {
+	res := hxres.New().
+		Trigger("refresh").
+		TriggerDetail(
+			itemCount.With(1),
+			showToast.With(toast{Message: "Added", Level: "info"}),
+		).
+		TriggerDetail(itemCount.With(2))
+
+	fmt.Print(res)
+	// Output: HX-Trigger: {"refresh":null,"itemCount":2,"showToast":{"message":"Added","level":"info"}}
+}
+
+func TestBuilder_TriggerDetail_error(t *testing.T) {
+	callback := hxres.NewEvent[func()]("callback")
+
+	w := httptest.NewRecorder()
+	err := hxres.New().
+		Trigger("refresh").
+		TriggerDetail(itemCount.With(1), callback.With(func() {})).
+		Apply(w)
+
+	if !errors.Is(err, hxres.ErrInvalid) || err.Error() != "hxres: invalid header value: callback detail: json: unsupported type: func()" {
+		t.Errorf("got error %v, want ErrInvalid", err)
+	}
+	if got, want := w.Header().Get("HX-Trigger"), `{"refresh":null,"itemCount":1}`; got != want {
+		t.Errorf("got HX-Trigger %s, want %s", got, want)
+	}
+}
+
+func ExampleBuilder_TriggerDetailAfterSettle() {
+	fmt.Print(hxres.New().TriggerDetailAfterSettle(itemCount.With(3)))
+	// Output: HX-Trigger-After-Settle: {"itemCount":3}
+}
+
+func ExampleBuilder_TriggerDetailAfterSwap() {
+	fmt.Print(hxres.New().TriggerDetailAfterSwap(itemCount.With(3)))
+	// Output: HX-Trigger-After-Swap: {"itemCount":3}
+}
+
+func ExampleBuilder_LocationExtended() {
+	fmt.Print(hxres.New().LocationExtended(
+		hxres.NewLocation("/contacts/%d", 1).
+			Source("#contact-form").
+			Event("submit").
+			Target(htmx.TargetRelative(htmx.Closest, "main")).
+			SwapExtended(swap.New().Strategy(swap.OuterHTML).IgnoreTitle()).
+			Values(map[string]int{"page": 2}).
+			Headers(map[string]string{"X-Source": "location"}).
+			Select("#contact"),
+	))
+	// Output: HX-Location: {"path":"/contacts/1","source":"#contact-form","event":"submit","target":"closest main","swap":"outerHTML ignoreTitle:true","values":{"page":2},"headers":{"X-Source":"location"},"select":"#contact"}
+}
+
+func TestBuilder_LocationExtended_error(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := hxres.New().
+		LocationExtended(hxres.NewLocation("/contacts").Values(map[string]any{"page": make(chan int)})).
+		Reswap(swap.OuterHTML).
+		Apply(w)
+
+	if !errors.Is(err, hxres.ErrInvalid) || err.Error() != "hxres: invalid header value: HX-Location values: json: unsupported type: chan int" {
+		t.Errorf("got error %v, want ErrInvalid", err)
+	}
+	if got := w.Header().Get("HX-Location"); got != "" {
+		t.Errorf("got HX-Location %s, want it left out", got)
+	}
+	if got := w.Header().Get("HX-Reswap"); got != "outerHTML" {
+		t.Errorf("got HX-Reswap %s, want the other headers applied", got)
+	}
+}
+
+func ExampleNewLocation() {
+	fmt.Println(hxres.NewLocation("/contacts"))
+	fmt.Println(hxres.NewLocation("/contacts").Handler("handleContacts").Swap(swap.InnerHTML))
+	// Output:
+	// /contacts
+	// {"path":"/contacts","handler":"handleContacts","swap":"innerHTML"}
+}
@@ -0,0 +1,199 @@
+package hxres
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+)
+
+// A LocationContext is a builder for the context object of an HX-Location header, to be passed to [Builder.LocationExtended].
+// These are the same options accepted by htmx.ajax() in the browser.
+type LocationContext struct {
+	path    string
+	source  htmx.StandardCSSSelector
+	event   trigger.TriggerEvent
+	handler string
+	target  htmx.TargetSelector
+	swap    string
+	values  any
+	headers any
+	sel     htmx.StandardCSSSelector
+}
+
+// NewLocation starts a builder chain for an HX-Location context object, redirecting to the given path.
+func NewLocation(path string, a ...any) *LocationContext {
+	if len(a) > 0 {
+		path = fmt.Sprintf(path, a...)
+	}
+	return &LocationContext{
+		path:    path,
+		source:  "",
+		event:   "",
+		handler: "",
+		target:  "",
+		swap:    "",
+		values:  nil,
+		headers: nil,
+		sel:     "",
+	}
+}
+
+// Source sets the source element of the request.
+func (l *LocationContext) Source(selector htmx.StandardCSSSelector) *LocationContext {
+	l.source = selector
+	return l
+}
+
+// Event sets the event that “triggered” the request.
+func (l *LocationContext) Event(event trigger.TriggerEvent) *LocationContext {
+	l.event = event
+	return l
+}
+
+// Handler sets a JavaScript callback that will handle the response HTML.
+func (l *LocationContext) Handler(handler string) *LocationContext {
+	l.handler = handler
+	return l
+}
+
+// Target sets the target to swap the response into, instead of the default document.body.
+func (l *LocationContext) Target(extendedSelector htmx.TargetSelector) *LocationContext {
+	l.target = extendedSelector
+	return l
+}
+
+// Swap sets how the response will be swapped in relative to the target.
+//
+// For modifiers, see [LocationContext.SwapExtended].
+func (l *LocationContext) Swap(strategy swap.Strategy) *LocationContext {
+	l.swap = string(strategy)
+	return l
+}
+
+// SwapExtended sets how the response will be swapped in relative to the target, with modifiers.
+//
+// For documentation about modifiers, see [swap.Builder].
+func (l *LocationContext) SwapExtended(swap *swap.Builder) *LocationContext {
+	l.swap = swap.String()
+	return l
+}
+
+// Values sets values to submit with the request, marshaled from a struct or map, like [htmx.HX.Vals].
+func (l *LocationContext) Values(values any) *LocationContext {
+	l.values = values
+	return l
+}
+
+// Headers sets headers to submit with the request, marshaled from a struct or map, like [htmx.HX.Headers].
+func (l *LocationContext) Headers(headers any) *LocationContext {
+	l.headers = headers
+	return l
+}
+
+// Select allows you to select the content you want swapped from a response.
+func (l *LocationContext) Select(selector htmx.StandardCSSSelector) *LocationContext {
+	l.sel = selector
+	return l
+}
+
+// locationJSON is the wire format of a LocationContext.
+type locationJSON struct {
+	Path    string          `json:"path"`
+	Source  string          `json:"source,omitempty"`
+	Event   string          `json:"event,omitempty"`
+	Handler string          `json:"handler,omitempty"`
+	Target  string          `json:"target,omitempty"`
+	Swap    string          `json:"swap,omitempty"`
+	Values  json.RawMessage `json:"values,omitempty"`
+	Headers json.RawMessage `json:"headers,omitempty"`
+	Select  string          `json:"select,omitempty"`
+}
+
+// String returns the HX-Location header value, or an empty string if the values or headers can't be marshaled.
+func (l *LocationContext) String() string {
+	value, _ := l.Encode()
+	return value
+}
+
+// Encode returns the HX-Location header value.
+// If only a path is set, this is the bare path. Otherwise it is a JSON context object.
+// It returns an error wrapping [ErrInvalid] if the values or headers can't be marshaled.
+func (l *LocationContext) Encode() (string, error) {
+	if l.isPathOnly() {
+		return l.path, nil
+	}
+
+	values, err := marshalObject("values", l.values)
+	if err != nil {
+		return "", err
+	}
+	headers, err := marshalObject("headers", l.headers)
+	if err != nil {
+		return "", err
+	}
+	bytes, err := json.Marshal(locationJSON{
+		Path:    l.path,
+		Source:  string(l.source),
+		Event:   string(l.event),
+		Handler: l.handler,
+		Target:  string(l.target),
+		Swap:    l.swap,
+		Values:  values,
+		Headers: headers,
+		Select:  string(l.sel),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrInvalid, Location, err)
+	}
+	return string(bytes), nil
+}
+
+// isPathOnly checks if the context has no options beyond the path.
+func (l *LocationContext) isPathOnly() bool {
+	return l.source == "" &&
+		l.event == "" &&
+		l.handler == "" &&
+		l.target == "" &&
+		l.swap == "" &&
+		l.values == nil &&
+		l.headers == nil &&
+		l.sel == ""
+}
+
+// marshalObject marshals an optional values or headers object.
+func marshalObject(name string, value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %s: %w", ErrInvalid, Location, name, err)
+	}
+	return bytes, nil
+}
+
+// LocationExtended allows you to do a client-side redirect that does not do a full page reload, with options for how the new content is requested and swapped.
+//
+//	hxres.New().LocationExtended(
+//		hxres.NewLocation("/contacts").
+//			Target("#content").
+//			Swap(swap.OuterHTML),
+//	)
+//
+// If the values or headers can't be marshaled, the header is left out, and the error is returned from [Builder.Apply].
+//
+// HTMX Header: [HX-Location]
+//
+// [HX-Location]: https://htmx.org/headers/hx-location/
+func (b *Builder) LocationExtended(location *LocationContext) *Builder {
+	value, err := location.Encode()
+	if err != nil {
+		b.errs = append(b.errs, err)
+		return b
+	}
+	b.headers[Location] = value
+	return b
+}
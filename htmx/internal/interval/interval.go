@@ -0,0 +1,20 @@
+// Package interval parses htmx timing declarations.
+package interval
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Parse parses an htmx timing declaration, like "500ms" or "1s", into a duration. Like htmx, a bare number is in milliseconds.
+func Parse(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	ms, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timing %q", s)
+	}
+	return time.Duration(ms * float64(time.Millisecond)), nil
+}
@@ -0,0 +1,30 @@
+package interval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/will-wow/typed-htmx-go/htmx/internal/interval"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{input: "500ms", want: 500 * time.Millisecond},
+		{input: "1.5s", want: 1500 * time.Millisecond},
+		{input: "2m", want: 2 * time.Minute},
+		{input: "250", want: 250 * time.Millisecond},
+	}
+	for _, test := range tests {
+		got, err := interval.Parse(test.input)
+		if err != nil || got != test.want {
+			t.Errorf("Parse(%q) = %v, %v, want %v", test.input, got, err, test.want)
+		}
+	}
+
+	if _, err := interval.Parse("soon"); err == nil {
+		t.Error("expected an error for an invalid interval")
+	}
+}
@@ -0,0 +1,6 @@
+// package target defines the selector type for hx-target, so it can be shared by packages that the htmx package imports.
+package target
+
+// A Selector is a CSS selector, or a non-standard selector for the hx-target attribute.
+// It's exported as [htmx.TargetSelector].
+type Selector string
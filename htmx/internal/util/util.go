@@ -27,3 +27,24 @@ func MakeRelativeSelector[Modifier ~string, Selector ~string]() func(Modifier, s
 		return Selector(fmt.Sprintf("%s %s", modifier, selector))
 	}
 }
+
+// ParseBool parses the "true" or "false" value of a modifier.
+func ParseBool(s string) (bool, error) {
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean %q", s)
+	}
+}
+
+// CutLast slices s around the last instance of sep, like [strings.Cut] from the end.
+// If sep is not found, it returns "", s, false.
+func CutLast(s string, sep string) (before string, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return "", s, false
+}
@@ -56,3 +56,22 @@ func TestMakeRelativeSelector(t *testing.T) {
 		t.Errorf(`got "%v", want "%v"`, got, want)
 	}
 }
+
+func TestCutLast(t *testing.T) {
+	tests := []struct {
+		s      string
+		before string
+		after  string
+		found  bool
+	}{
+		{s: "#a:hover:top", before: "#a:hover", after: "top", found: true},
+		{s: "window:bottom", before: "window", after: "bottom", found: true},
+		{s: "top", before: "", after: "top", found: false},
+	}
+	for _, tt := range tests {
+		before, after, found := util.CutLast(tt.s, ":")
+		if before != tt.before || after != tt.after || found != tt.found {
+			t.Errorf("CutLast(%q) = %q, %q, %t, want %q, %q, %t", tt.s, before, after, found, tt.before, tt.after, tt.found)
+		}
+	}
+}
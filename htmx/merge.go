@@ -0,0 +1,133 @@
+package htmx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrConflict is returned when merging two different values for an attribute that can only have one, like two hx-target attributes.
+var ErrConflict = errors.New("htmx: conflicting attribute values")
+
+// mergedAttr is an attribute being merged, in the order it was first set.
+type mergedAttr struct {
+	key   string
+	value any
+}
+
+// mergeAttrs combines attributes with the same key, following the semantics of each htmx attribute:
+//
+//   - hx-ext lists are combined, without duplicates.
+//   - hx-on handlers for the same event run one after another.
+//   - hx-vals and hx-headers JSON objects are deep-merged.
+//   - classes runs from the class-tools extension run in parallel.
+//
+// Other attributes with different values are conflicts, which are returned as errors wrapping [ErrConflict].
+// For conflicts, the last value is kept.
+func mergeAttrs(attrs []mergedAttr) ([]mergedAttr, error) {
+	merged := make([]mergedAttr, 0, len(attrs))
+	index := make(map[string]int, len(attrs))
+	var errs []error
+	for _, attr := range attrs {
+		i, ok := index[attr.key]
+		if !ok {
+			index[attr.key] = len(merged)
+			merged = append(merged, attr)
+			continue
+		}
+		value, err := mergeValue(attr.key, merged[i].value, attr.value)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		merged[i].value = value
+	}
+	return merged, errors.Join(errs...)
+}
+
+// mergeValue merges two values for the same attribute.
+func mergeValue(key string, prev any, next any) (any, error) {
+	// Identical values can't conflict, even when they have a type that can't be rendered.
+	if reflect.DeepEqual(prev, next) {
+		return prev, nil
+	}
+	prevText, prevOK, prevErr := attrValue(prev)
+	nextText, nextOK, nextErr := attrValue(next)
+	switch {
+	case prevErr != nil || nextErr != nil:
+		return next, fmt.Errorf("%w: %s has values %v and %v", ErrConflict, key, prev, next)
+	// Attributes that don't render can't conflict.
+	case !prevOK:
+		return next, nil
+	case !nextOK:
+		return prev, nil
+	// Boolean attributes are the same whenever they're both set.
+	case prevText == nil && nextText == nil:
+		return next, nil
+	case prevText == nil || nextText == nil:
+		return next, fmt.Errorf("%w: %s is set as both a flag and a value", ErrConflict, key)
+	case *prevText == *nextText:
+		return next, nil
+	}
+
+	a, b := *prevText, *nextText
+	name := strings.TrimPrefix(key, "data-")
+	switch {
+	case name == string(Ext):
+		return joinUnique(a, b), nil
+	case strings.HasPrefix(name, "hx-on:") || strings.HasPrefix(name, "hx-on-"):
+		return strings.TrimRight(strings.TrimSpace(a), ";") + "; " + b, nil
+	case name == string(Vals) || name == string(Headers):
+		merged, err := mergeJSON(a, b)
+		if err != nil {
+			return next, fmt.Errorf("%w: %s has values %s and %s, which aren't both JSON objects", ErrConflict, key, a, b)
+		}
+		return merged, nil
+	case name == "classes":
+		return a + " & " + b, nil
+	}
+	return next, fmt.Errorf("%w: %s is set to both %q and %q", ErrConflict, key, a, b)
+}
+
+// joinUnique combines two comma-separated lists, leaving out duplicates.
+func joinUnique(a string, b string) string {
+	var items []string
+	for _, item := range strings.Split(a+","+b, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !slices.Contains(items, item) {
+			items = append(items, item)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// mergeJSON deep-merges two JSON objects. Keys in b override the same keys in a, unless they're both objects.
+func mergeJSON(a string, b string) (string, error) {
+	var aObj, bObj map[string]any
+	if err := json.Unmarshal([]byte(a), &aObj); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal([]byte(b), &bObj); err != nil {
+		return "", err
+	}
+	if aObj == nil || bObj == nil {
+		return "", errors.New("not a JSON object")
+	}
+	bytes, err := json.Marshal(deepMerge(aObj, bObj))
+	return string(bytes), err
+}
+
+func deepMerge(a map[string]any, b map[string]any) map[string]any {
+	for key, bValue := range b {
+		aMap, aOK := a[key].(map[string]any)
+		bMap, bOK := bValue.(map[string]any)
+		if aOK && bOK {
+			a[key] = deepMerge(aMap, bMap)
+			continue
+		}
+		a[key] = bValue
+	}
+	return a
+}
@@ -0,0 +1,155 @@
+package htmx_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	. "github.com/maragudk/gomponents/html"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/classtools"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/preload"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/responsetargets"
+	"github.com/will-wow/typed-htmx-go/htmx/on"
+)
+
+func ExampleMergeTemplAttrs() {
+	attrs, err := htmx.MergeTemplAttrs(
+		templHx.Ext(preload.Extension),
+		templHx.Ext(responsetargets.Extension),
+		templHx.On(on.AfterRequest, "this.reset()"),
+		templHx.On(on.AfterRequest, "done()"),
+		templHx.Target("#a"),
+		templHx.Target("#b"),
+	)
+
+	fmt.Println(attrs)
+	fmt.Println(err)
+	// Output:
+	// map[hx-ext:preload,response-targets hx-on:htmx:after-request:this.reset(); done() hx-target:#b]
+	// htmx: conflicting attribute values: hx-target is set to both "#a" and "#b"
+}
+
+func ExampleMergeGomponentsAttrs() {
+	attrs, err := htmx.MergeGomponentsAttrs(
+		gomHx.Vals(map[string]any{"page": 1, "filter": map[string]string{"name": "a"}}),
+		gomHx.Vals(map[string]any{"filter": map[string]string{"email": "b"}}),
+		classtools.Classes(gomHx, classtools.Add("loading", 0)),
+		classtools.Classes(gomHx, classtools.Remove("hidden", time.Second)),
+	)
+
+	_ = Div(attrs).Render(os.Stdout)
+	fmt.Println()
+	fmt.Println(err)
+	// Output:
+	// <div hx-vals="{&#34;filter&#34;:{&#34;email&#34;:&#34;b&#34;,&#34;name&#34;:&#34;a&#34;},&#34;page&#34;:1}" classes="add loading:0s &amp; remove hidden:1s"></div>
+	// <nil>
+}
+
+func TestMergeTemplAttrs(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    []templ.Attributes
+		want     templ.Attributes
+		conflict bool
+	}{
+		{
+			name:  "same value",
+			attrs: []templ.Attributes{templHx.Get("/a"), templHx.Get("/a")},
+			want:  templ.Attributes{"hx-get": "/a"},
+		},
+		{
+			name:  "same unrenderable value",
+			attrs: []templ.Attributes{{"x-data": []string{"a"}}, {"x-data": []string{"a"}}},
+			want:  templ.Attributes{"x-data": []string{"a"}},
+		},
+		{
+			name:     "different unrenderable values",
+			attrs:    []templ.Attributes{{"x-data": []string{"a"}}, {"x-data": []string{"b"}}},
+			want:     templ.Attributes{"x-data": []string{"b"}},
+			conflict: true,
+		},
+		{
+			name:  "flags",
+			attrs: []templ.Attributes{templHx.Preserve(), templHx.Preserve()},
+			want:  templ.Attributes{"hx-preserve": true},
+		},
+		{
+			name:  "unset flag",
+			attrs: []templ.Attributes{{"hx-disable": false}, templHx.Disable()},
+			want:  templ.Attributes{"hx-disable": true},
+		},
+		{
+			name:  "duplicate extensions",
+			attrs: []templ.Attributes{templHx.Ext(preload.Extension, "sse"), templHx.Ext("sse"), {"hx-ext": "ws"}},
+			want:  templ.Attributes{"hx-ext": "preload,sse,ws"},
+		},
+		{
+			name:  "data prefixed extensions",
+			attrs: []templ.Attributes{{"data-hx-ext": "sse"}, {"data-hx-ext": "ws"}},
+			want:  templ.Attributes{"data-hx-ext": "sse,ws"},
+		},
+		{
+			name:  "handlers with semicolons",
+			attrs: []templ.Attributes{templHx.On("click", "a();"), templHx.On("click", "b()")},
+			want:  templ.Attributes{"hx-on:click": "a(); b()"},
+		},
+		{
+			name:  "different events",
+			attrs: []templ.Attributes{templHx.On("click", "a()"), templHx.On("focus", "b()")},
+			want:  templ.Attributes{"hx-on:click": "a()", "hx-on:focus": "b()"},
+		},
+		{
+			name:  "headers",
+			attrs: []templ.Attributes{templHx.Headers(map[string]string{"A": "1", "B": "1"}), templHx.Headers(map[string]string{"B": "2"})},
+			want:  templ.Attributes{"hx-headers": `{"A":"1","B":"2"}`},
+		},
+		{
+			name:     "js vals",
+			attrs:    []templ.Attributes{templHx.ValsJS(map[string]string{"a": "1"}), templHx.Vals(map[string]string{"b": "2"})},
+			want:     templ.Attributes{"hx-vals": `{"b":"2"}`},
+			conflict: true,
+		},
+		{
+			name:     "flag and value",
+			attrs:    []templ.Attributes{{"hx-boost": true}, templHx.Boost(false)},
+			want:     templ.Attributes{"hx-boost": "false"},
+			conflict: true,
+		},
+		{
+			name:     "last target wins",
+			attrs:    []templ.Attributes{templHx.Target("#a"), templHx.Target("#b"), templHx.Target("#c")},
+			want:     templ.Attributes{"hx-target": "#c"},
+			conflict: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := htmx.MergeTemplAttrs(tt.attrs...)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if tt.conflict != errors.Is(err, htmx.ErrConflict) {
+				t.Errorf("got error %v, want conflict: %t", err, tt.conflict)
+			}
+			if lenient := htmx.TemplAttrs(tt.attrs...); fmt.Sprint(lenient) != fmt.Sprint(tt.want) {
+				t.Errorf("TemplAttrs got %v, want %v", lenient, tt.want)
+			}
+		})
+	}
+}
+
+func TestGomponentsGroup_merge(t *testing.T) {
+	group := htmx.GomponentsGroup(
+		gomHx.Ext("sse"),
+		htmx.GomponentsGroup(gomHx.Ext("ws"), gomHx.Target("#a")),
+		gomHx.Target("#b"),
+	)
+	if got, want := group.String(), ` hx-ext="sse,ws" hx-target="#b"`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -2,6 +2,7 @@
 package on
 
 // An Event is a camel-cased HTMX event name, used as an argument to the [htmx.HX.On] attribute.
+// With htmx 2.x, [htmx.HX.On] renders htmx events with the hx-on::event shorthand.
 type Event = string
 
 const (
@@ -17,6 +18,7 @@ const (
 	BeforeRequest         Event = "htmx:before-request"           // triggered before an AJAX request is made
 	BeforeSwap            Event = "htmx:before-swap"              // triggered before a swap is done, allows you to configure the swap
 	BeforeSend            Event = "htmx:before-send"              // triggered just before an ajax request is sent
+	BeforeTransition      Event = "htmx:before-transition"        // triggered before a View Transition wrapped swap occurs
 	ConfigRequest         Event = "htmx:config-request"           // triggered before the request, allows you to customize parameters, headers
 	Confirm               Event = "htmx:confirm"                  // triggered after a trigger occurs on an element, allows you to cancel (or delay) issuing the AJAX request
 	HistoryCacheError     Event = "htmx:history-cache-error"      // triggered on an error during cache writing
@@ -26,7 +28,7 @@ const (
 	HistoryRestore        Event = "htmx:history-restore"          // triggered when htmx handles a history restoration action
 	BeforeHistorySave     Event = "htmx:before-history-save"      // triggered before content is saved to the history cache
 	Load                  Event = "htmx:load"                     // triggered when new content is added to the DOM
-	NoSSESourceError      Event = "htmx:no-sse-source-error"      // triggered when an element refers to a SSE event in its trigger, but no parent SSE source has been defined
+	NoSSESourceError      Event = "htmx:no-sse-source-error"      // triggered when an element refers to a SSE event in its trigger, but no parent SSE source has been defined. Removed in htmx 2.x.
 	OnLoadError           Event = "htmx:on-load-error"            // triggered when an exception occurs during the onLoad handling in htmx
 	OOBAfterSwap          Event = "htmx:oob-after-swap"           // triggered after an out of band element as been swapped in
 	OOBBeforeSwap         Event = "htmx:oob-before-swap"          // triggered before an out of band element swap is done, allows you to configure the swap
@@ -35,14 +37,16 @@ const (
 	PushedIntoHistory     Event = "htmx:pushed-into-history"      // triggered after an url is pushed into history
 	ResponseError         Event = "htmx:response-error"           // triggered when an HTTP response error (non-200 or 300 response code) occurs
 	SendError             Event = "htmx:send-error"               // triggered when a network error prevents an HTTP request from happening
-	SSEError              Event = "htmx:sse-error"                // triggered when an error occurs with a SSE source
-	SSEOpen               Event = "htmx:sse-open"                 // triggered when a SSE source is opened
+	SSEError              Event = "htmx:sse-error"                // triggered when an error occurs with a SSE source. In htmx 2.x, triggered by the sse extension.
+	SSEOpen               Event = "htmx:sse-open"                 // triggered when a SSE source is opened. In htmx 2.x, triggered by the sse extension.
 	SwapError             Event = "htmx:swap-error"               // triggered when an error occurs during the swap phase
 	TargetError           Event = "htmx:target-error"             // triggered when an invalid target is specified
 	Timeout               Event = "htmx:timeout"                  // triggered when a request timeout occurs
+	Trigger               Event = "htmx:trigger"                  // triggered whenever an AJAX request would be, even if no AJAX request is specified
+	ValidateURL           Event = "htmx:validate-url"             // triggered before a request is made, allowing you to validate the URL
 	ValidationValidate    Event = "htmx:validation:validate"      // triggered before an element is validated
 	ValidationFailed      Event = "htmx:validation:failed"        // triggered when an element fails validation
-	ValidationHalted      Event = "htmx:validation:halter"        // triggered when a request is halted due to validation errors
+	ValidationHalted      Event = "htmx:validation:halted"        // triggered when a request is halted due to validation errors
 	XHRAbort              Event = "htmx:xhr:abort"                // triggered when an ajax request aborts
 	XHRLoadEnd            Event = "htmx:xhr:loadend"              // triggered when an ajax request ends
 	XHRLoadStart          Event = "htmx:xhr:loadstart"            // triggered when an ajax request starts
@@ -0,0 +1,372 @@
+// package oob composes a single response from a primary fragment and any number of out-of-band fragments, for both templ and gomponents.
+//
+// Each out-of-band fragment has an hx-swap-oob attribute injected into its root element, so the same component can be rendered in place on one page, and swapped out-of-band from another response.
+//
+//	component := oob.NewTempl(
+//		rowComponent(row),
+//		oob.Templ(counterBadge(count)),
+//		oob.Templ(toast("Saved!")).Strategy(swap.BeforeEnd).Target("#toasts"),
+//	)
+//
+// Table rows and other elements that can't stand on their own in the DOM (<tr>, <td>, <li>, <option>, etc.) are automatically wrapped in a <template> tag.
+// In htmx 1.x this requires the page to enable [hxconfig.Builder.UseTemplateFragments]. Use a [Composer] built from the page's htmx version and config to check for it.
+//
+// See [hx-swap-oob].
+//
+// [hx-swap-oob]: https://htmx.org/attributes/hx-swap-oob/
+package oob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+// ErrNoElement is returned when an out-of-band fragment does not render an element to attach hx-swap-oob to.
+var ErrNoElement = errors.New("oob: fragment must render an element")
+
+// ErrSwapOOBSet is returned when an out-of-band fragment's root element already has an hx-swap-oob attribute, which would conflict with the injected one.
+var ErrSwapOOBSet = errors.New("oob: fragment root already has an hx-swap-oob attribute")
+
+// ErrTemplateFragments is returned by a strict [Composer] when a fragment must be wrapped in a <template> tag, but the page's config does not enable template fragments.
+var ErrTemplateFragments = errors.New("oob: fragment must be wrapped in a <template>, which requires hxconfig UseTemplateFragments(true)")
+
+// A Composer renders out-of-band responses for pages with a known htmx config.
+// It checks that fragments that must be wrapped in a <template> tag can be parsed by the page.
+type Composer struct {
+	templateFragments bool
+	strict            bool
+	logger            *slog.Logger
+}
+
+// defaultComposer is used by the package-level functions, which don't know the page config.
+var defaultComposer = &Composer{
+	templateFragments: true,
+	strict:            false,
+	logger:            nil,
+}
+
+// NewComposer creates a Composer for pages using the given version of htmx and htmx config.
+// By default, it logs a warning with [slog.Default] when a fragment is wrapped in a <template> tag, but the config does not enable template fragments.
+//
+// htmx 2 always supports template fragments. A nil config assumes they're enabled, like the package-level functions.
+func NewComposer(v htmx.Version, config *hxconfig.Builder) *Composer {
+	enabled := true
+	if config != nil && v < htmx.V2 {
+		// Invalid keys are reported when the config is rendered, so they're ignored here.
+		built, _ := config.BuildFor(v)
+		enabled, _ = built["useTemplateFragments"].(bool)
+	}
+	return &Composer{
+		templateFragments: enabled,
+		strict:            false,
+		logger:            nil,
+	}
+}
+
+// Strict makes the Composer refuse to render fragments that need template fragments when they are not enabled, returning [ErrTemplateFragments].
+func (c *Composer) Strict() *Composer {
+	c.strict = true
+	return c
+}
+
+// Logger sets the logger used for warnings, instead of [slog.Default].
+func (c *Composer) Logger(logger *slog.Logger) *Composer {
+	c.logger = logger
+	return c
+}
+
+// renderFunc renders a component or node to a writer.
+type renderFunc func(ctx context.Context, w io.Writer) error
+
+// A Fragment is a component or node to be swapped in out-of-band, with an optional swap strategy and target.
+type Fragment struct {
+	render   renderFunc
+	strategy swap.Strategy
+	selector htmx.StandardCSSSelector
+}
+
+// Templ starts a builder chain for an out-of-band fragment rendered from a templ component.
+// By default, the fragment replaces the element with the same id.
+func Templ(component templ.Component) *Fragment {
+	return newFragment(component.Render)
+}
+
+// Gomponents starts a builder chain for an out-of-band fragment rendered from a gomponents node.
+// By default, the fragment replaces the element with the same id.
+func Gomponents(node g.Node) *Fragment {
+	return newFragment(func(_ context.Context, w io.Writer) error {
+		return node.Render(w)
+	})
+}
+
+func newFragment(render renderFunc) *Fragment {
+	return &Fragment{
+		render:   render,
+		strategy: "",
+		selector: "",
+	}
+}
+
+// Strategy sets how the fragment is swapped in, like [htmx.HX.SwapOOBWithStrategy].
+func (f *Fragment) Strategy(strategy swap.Strategy) *Fragment {
+	f.strategy = strategy
+	return f
+}
+
+// Target swaps the fragment into the elements matching a CSS selector, instead of the element with the same id, like [htmx.HX.SwapOOBSelector].
+// If no strategy is set, the fragment uses outerHTML.
+func (f *Fragment) Target(selector htmx.StandardCSSSelector) *Fragment {
+	f.selector = selector
+	return f
+}
+
+// value returns the hx-swap-oob attribute value for the fragment.
+func (f *Fragment) value() string {
+	switch {
+	case f.selector != "" && f.strategy == "":
+		return fmt.Sprintf("%s:%s", swap.OuterHTML, f.selector)
+	case f.selector != "":
+		return fmt.Sprintf("%s:%s", f.strategy, f.selector)
+	case f.strategy != "":
+		return string(f.strategy)
+	default:
+		return "true"
+	}
+}
+
+// NewTempl returns a templ component that renders the primary component, followed by each out-of-band fragment.
+// The primary component may be nil, for responses that only contain out-of-band swaps.
+// Fragments are wrapped in a <template> tag when needed, without checking the page config. To check it, use [Composer.NewTempl].
+func NewTempl(primary templ.Component, fragments ...*Fragment) templ.Component {
+	return defaultComposer.NewTempl(primary, fragments...)
+}
+
+// NewGomponents returns a gomponents node that renders the primary node, followed by each out-of-band fragment.
+// The primary node may be nil, for responses that only contain out-of-band swaps.
+// Fragments built from templ components are rendered with a background context. To pass the request context, use [NewGomponentsContext].
+// Fragments are wrapped in a <template> tag when needed, without checking the page config. To check it, use [Composer.NewGomponents].
+func NewGomponents(primary g.Node, fragments ...*Fragment) g.Node {
+	return defaultComposer.NewGomponents(primary, fragments...)
+}
+
+// NewGomponentsContext is like [NewGomponents], but renders fragments built from templ components with the given context.
+func NewGomponentsContext(ctx context.Context, primary g.Node, fragments ...*Fragment) g.Node {
+	return defaultComposer.NewGomponentsContext(ctx, primary, fragments...)
+}
+
+// NewTempl returns a templ component that renders the primary component, followed by each out-of-band fragment.
+// The primary component may be nil, for responses that only contain out-of-band swaps.
+func (c *Composer) NewTempl(primary templ.Component, fragments ...*Fragment) templ.Component {
+	var render renderFunc
+	if primary != nil {
+		render = primary.Render
+	}
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return c.compose(ctx, w, render, fragments)
+	})
+}
+
+// NewGomponents returns a gomponents node that renders the primary node, followed by each out-of-band fragment.
+// The primary node may be nil, for responses that only contain out-of-band swaps.
+// Fragments built from templ components are rendered with a background context. To pass the request context, use [Composer.NewGomponentsContext].
+func (c *Composer) NewGomponents(primary g.Node, fragments ...*Fragment) g.Node {
+	return c.NewGomponentsContext(context.Background(), primary, fragments...)
+}
+
+// NewGomponentsContext is like [Composer.NewGomponents], but renders fragments built from templ components with the given context.
+func (c *Composer) NewGomponentsContext(ctx context.Context, primary g.Node, fragments ...*Fragment) g.Node {
+	var render renderFunc
+	if primary != nil {
+		render = func(_ context.Context, w io.Writer) error {
+			return primary.Render(w)
+		}
+	}
+	return g.NodeFunc(func(w io.Writer) error {
+		return c.compose(ctx, w, render, fragments)
+	})
+}
+
+// compose writes the primary content and fragments.
+// Fragments are rendered to a buffer first, so nothing is written if a fragment fails to render.
+func (c *Composer) compose(ctx context.Context, w io.Writer, primary renderFunc, fragments []*Fragment) error {
+	var buf bytes.Buffer
+
+	if primary != nil {
+		if err := primary(ctx, &buf); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range fragments {
+		if err := c.writeFragment(ctx, &buf, f); err != nil {
+			return err
+		}
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// writeFragment renders the fragment, with hx-swap-oob injected into its root element.
+// A root element that already sets hx-swap-oob is rejected with [ErrSwapOOBSet], instead of rendering the attribute twice.
+// Root elements that can't stand on their own are wrapped in a <template> tag.
+func (c *Composer) writeFragment(ctx context.Context, w *bytes.Buffer, f *Fragment) error {
+	var buf bytes.Buffer
+	if err := f.render(ctx, &buf); err != nil {
+		return err
+	}
+
+	content := buf.Bytes()
+	start, end := findTagName(content)
+	if start == -1 {
+		return ErrNoElement
+	}
+
+	if hasSwapOOB(content[end:]) {
+		return ErrSwapOOBSet
+	}
+
+	tagName := strings.ToLower(string(content[start:end]))
+	wrap := requiresTemplate(tagName)
+	if wrap && !c.templateFragments {
+		if c.strict {
+			return fmt.Errorf("%w: <%s>", ErrTemplateFragments, tagName)
+		}
+		c.warn("oob: wrapping fragment in a <template>, but template fragments are not enabled", "tag", tagName)
+	}
+
+	if wrap {
+		_, _ = w.WriteString("<template>")
+	}
+	_, _ = w.Write(content[:end])
+	_, _ = fmt.Fprintf(w, ` %s="%s"`, htmx.SwapOOB, html.EscapeString(f.value()))
+	_, _ = w.Write(content[end:])
+	if wrap {
+		_, _ = w.WriteString("</template>")
+	}
+	return nil
+}
+
+// warn logs a warning to the configured logger.
+func (c *Composer) warn(msg string, args ...any) {
+	logger := c.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn(msg, args...)
+}
+
+// templateElements are elements that, by the HTML spec, can't stand on their own in the DOM, and must be wrapped in a <template> to be swapped out-of-band.
+var templateElements = map[string]bool{
+	"caption":  true,
+	"col":      true,
+	"colgroup": true,
+	"li":       true,
+	"optgroup": true,
+	"option":   true,
+	"tbody":    true,
+	"td":       true,
+	"tfoot":    true,
+	"th":       true,
+	"thead":    true,
+	"tr":       true,
+}
+
+// requiresTemplate checks if an element must be wrapped in a <template> to be swapped out-of-band.
+func requiresTemplate(tagName string) bool {
+	return templateElements[tagName]
+}
+
+// findTagName finds the start and end of the tag name of the first element in the content, skipping leading whitespace and comments.
+// Returns -1, -1 if the content does not start with an element.
+func findTagName(content []byte) (int, int) {
+	i := 0
+	for i < len(content) {
+		switch {
+		case isSpace(content[i]):
+			i++
+		case bytes.HasPrefix(content[i:], []byte("<!--")):
+			end := bytes.Index(content[i:], []byte("-->"))
+			if end == -1 {
+				return -1, -1
+			}
+			i += end + len("-->")
+		case content[i] == '<' && i+1 < len(content) && isLetter(content[i+1]):
+			start := i + 1
+			i = start
+			for i < len(content) && !isSpace(content[i]) && content[i] != '>' && content[i] != '/' {
+				i++
+			}
+			return start, i
+		default:
+			return -1, -1
+		}
+	}
+	return -1, -1
+}
+
+// hasSwapOOB checks if the attributes of a start tag, up to its closing >, include hx-swap-oob, with or without a data- prefix.
+func hasSwapOOB(attrs []byte) bool {
+	i := 0
+	for i < len(attrs) {
+		switch {
+		case attrs[i] == '>':
+			return false
+		case isSpace(attrs[i]) || attrs[i] == '/':
+			i++
+		default:
+			start := i
+			for i < len(attrs) && !isSpace(attrs[i]) && attrs[i] != '=' && attrs[i] != '>' && attrs[i] != '/' {
+				i++
+			}
+			name := strings.ToLower(string(attrs[start:i]))
+			if name == string(htmx.SwapOOB) || name == "data-"+string(htmx.SwapOOB) {
+				return true
+			}
+
+			for i < len(attrs) && isSpace(attrs[i]) {
+				i++
+			}
+			if i == len(attrs) || attrs[i] != '=' {
+				continue
+			}
+			i++
+			for i < len(attrs) && isSpace(attrs[i]) {
+				i++
+			}
+			if i < len(attrs) && (attrs[i] == '"' || attrs[i] == '\'') {
+				end := bytes.IndexByte(attrs[i+1:], attrs[i])
+				if end == -1 {
+					return false
+				}
+				i += end + 2
+				continue
+			}
+			for i < len(attrs) && !isSpace(attrs[i]) && attrs[i] != '>' {
+				i++
+			}
+		}
+	}
+	return false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
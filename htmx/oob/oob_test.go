@@ -0,0 +1,244 @@
+package oob_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+	"github.com/will-wow/typed-htmx-go/htmx/oob"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+func ExampleNewTempl() {
+	component := oob.NewTempl(
+		templ.Raw(`<tr id="row-1"><td>Joe</td></tr>`),
+		oob.Templ(templ.Raw(`<span id="count">3</span>`)),
+		oob.Templ(templ.Raw(`<div class="toast">Saved!</div>`)).
+			Strategy(swap.BeforeEnd).
+			Target("#toasts"),
+	)
+
+	_ = component.Render(context.Background(), os.Stdout)
+	// Output: <tr id="row-1"><td>Joe</td></tr><span hx-swap-oob="true" id="count">3</span><div hx-swap-oob="beforeend:#toasts" class="toast">Saved!</div>
+}
+
+func ExampleNewGomponents() {
+	node := oob.NewGomponents(
+		Tr(ID("row-1"), Td(g.Text("Joe"))),
+		oob.Gomponents(Span(ID("count"), g.Text("3"))),
+		oob.Gomponents(Div(ID("alerts"), g.Text("Saved!"))).Strategy(swap.AfterBegin),
+		oob.Gomponents(Hr()).Target("#divider"),
+	)
+
+	_ = node.Render(os.Stdout)
+	// Output: <tr id="row-1"><td>Joe</td></tr><span hx-swap-oob="true" id="count">3</span><div hx-swap-oob="afterbegin" id="alerts">Saved!</div><hr hx-swap-oob="outerHTML:#divider">
+}
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name      string
+		primary   templ.Component
+		fragments []*oob.Fragment
+		want      string
+		wantErr   error
+	}{
+		{
+			name:    "no primary",
+			primary: nil,
+			fragments: []*oob.Fragment{
+				oob.Templ(templ.Raw(`<p id="a">a</p>`)),
+			},
+			want:    `<p hx-swap-oob="true" id="a">a</p>`,
+			wantErr: nil,
+		},
+		{
+			name:    "leading whitespace and comments",
+			primary: templ.Raw(`<main></main>`),
+			fragments: []*oob.Fragment{
+				oob.Templ(templ.Raw("\n  <!-- note --> <p id=\"a\">a</p>")),
+			},
+			want:    "<main></main>\n  <!-- note --> <p hx-swap-oob=\"true\" id=\"a\">a</p>",
+			wantErr: nil,
+		},
+		{
+			name:    "self-closing root",
+			primary: nil,
+			fragments: []*oob.Fragment{
+				oob.Templ(templ.Raw(`<input/>`)),
+			},
+			want:    `<input hx-swap-oob="true"/>`,
+			wantErr: nil,
+		},
+		{
+			name:    "text fragment",
+			primary: templ.Raw(`<main></main>`),
+			fragments: []*oob.Fragment{
+				oob.Templ(templ.Raw(`just text`)),
+			},
+			want:    ``,
+			wantErr: oob.ErrNoElement,
+		},
+		{
+			name:    "escaped selector",
+			primary: nil,
+			fragments: []*oob.Fragment{
+				oob.Templ(templ.Raw(`<p>a</p>`)).Target(`[data-name="a"]`),
+			},
+			want:    `<p hx-swap-oob="outerHTML:[data-name=&#34;a&#34;]">a</p>`,
+			wantErr: nil,
+		},
+		{
+			name:    "existing swap oob",
+			primary: templ.Raw(`<main></main>`),
+			fragments: []*oob.Fragment{
+				oob.Templ(templ.Raw(`<p id="a" HX-SWAP-OOB="innerHTML">a</p>`)),
+			},
+			want:    ``,
+			wantErr: oob.ErrSwapOOBSet,
+		},
+		{
+			name:    "existing data swap oob",
+			primary: nil,
+			fragments: []*oob.Fragment{
+				oob.Templ(templ.Raw(`<p class='x > y' data-hx-swap-oob>a</p>`)),
+			},
+			want:    ``,
+			wantErr: oob.ErrSwapOOBSet,
+		},
+		{
+			name:    "swap oob in a value or child",
+			primary: nil,
+			fragments: []*oob.Fragment{
+				oob.Templ(templ.Raw(`<div title="hx-swap-oob" data-x=hx-swap-oob><p hx-swap-oob="true">a</p></div>`)),
+			},
+			want:    `<div hx-swap-oob="true" title="hx-swap-oob" data-x=hx-swap-oob><p hx-swap-oob="true">a</p></div>`,
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			err := oob.NewTempl(tt.primary, tt.fragments...).Render(context.Background(), &b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleComposer() {
+	composer := oob.NewComposer(htmx.V1, hxconfig.New().UseTemplateFragments(true))
+
+	node := composer.NewGomponents(
+		Div(ID("toast"), g.Text("Updated 2 users")),
+		oob.Gomponents(Tr(ID("user-1"), Td(g.Text("Joe")))),
+		oob.Gomponents(Li(g.Text("Kim"))).Strategy(swap.BeforeEnd).Target("#active-users"),
+	)
+
+	_ = node.Render(os.Stdout)
+	// Output: <div id="toast">Updated 2 users</div><template><tr hx-swap-oob="true" id="user-1"><td>Joe</td></tr></template><template><li hx-swap-oob="beforeend:#active-users">Kim</li></template>
+}
+
+func TestComposerTemplateFragments(t *testing.T) {
+	row := oob.Templ(templ.Raw(`<TR id="row"><td>1</td></TR>`))
+
+	t.Run("warns when template fragments are disabled", func(t *testing.T) {
+		var logs strings.Builder
+		composer := oob.NewComposer(htmx.V1, hxconfig.New()).
+			Logger(slog.New(slog.NewTextHandler(&logs, nil)))
+
+		var b strings.Builder
+		err := composer.NewTempl(nil, row).Render(context.Background(), &b)
+		if err != nil {
+			t.Fatalf("got error %v", err)
+		}
+
+		want := `<template><TR hx-swap-oob="true" id="row"><td>1</td></TR></template>`
+		if got := b.String(); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if !strings.Contains(logs.String(), "tag=tr") {
+			t.Errorf("expected a warning for tr, got %q", logs.String())
+		}
+	})
+
+	t.Run("refuses in strict mode", func(t *testing.T) {
+		composer := oob.NewComposer(htmx.V1, hxconfig.New().UseTemplateFragments(false)).Strict()
+
+		var b strings.Builder
+		err := composer.NewTempl(templ.Raw(`<p>ok</p>`), row).Render(context.Background(), &b)
+		if !errors.Is(err, oob.ErrTemplateFragments) {
+			t.Fatalf("got error %v, want %v", err, oob.ErrTemplateFragments)
+		}
+		if b.Len() != 0 {
+			t.Errorf("expected nothing to be written, got %s", b.String())
+		}
+	})
+
+	t.Run("htmx 2 always supports template fragments", func(t *testing.T) {
+		composer := oob.NewComposer(htmx.V2, hxconfig.New()).Strict()
+
+		var b strings.Builder
+		err := composer.NewTempl(nil, row).Render(context.Background(), &b)
+		if err != nil {
+			t.Fatalf("got error %v", err)
+		}
+		if want := `<template><TR hx-swap-oob="true" id="row"><td>1</td></TR></template>`; b.String() != want {
+			t.Errorf("got %s, want %s", b.String(), want)
+		}
+	})
+
+	t.Run("nil config", func(t *testing.T) {
+		composer := oob.NewComposer(htmx.V1, nil).Strict()
+
+		var b strings.Builder
+		if err := composer.NewTempl(nil, row).Render(context.Background(), &b); err != nil {
+			t.Fatalf("got error %v", err)
+		}
+	})
+
+	t.Run("does not wrap other elements", func(t *testing.T) {
+		composer := oob.NewComposer(htmx.V1, hxconfig.New()).Strict()
+
+		var b strings.Builder
+		err := composer.NewTempl(nil, oob.Templ(templ.Raw(`<table id="t"></table>`))).Render(context.Background(), &b)
+		if err != nil {
+			t.Fatalf("got error %v", err)
+		}
+		if want := `<table hx-swap-oob="true" id="t"></table>`; b.String() != want {
+			t.Errorf("got %s, want %s", b.String(), want)
+		}
+	})
+}
+
+func TestNewGomponentsContext(t *testing.T) {
+	type key struct{}
+	user := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<span id="user">%s</span>`, ctx.Value(key{}))
+		return err
+	})
+	ctx := context.WithValue(context.Background(), key{}, "Joe")
+
+	var b strings.Builder
+	if err := oob.NewGomponentsContext(ctx, P(g.Text("ok")), oob.Templ(user)).Render(&b); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if want := `<p>ok</p><span hx-swap-oob="true" id="user">Joe</span>`; b.String() != want {
+		t.Errorf("got %s, want %s", b.String(), want)
+	}
+}
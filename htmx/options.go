@@ -0,0 +1,124 @@
+package htmx
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/will-wow/typed-htmx-go/htmx/version"
+)
+
+// A Version is a major version of htmx, which changes how some attributes are rendered.
+type Version = version.Version
+
+const (
+	V1 = version.V1 // htmx 1.x, tested against 1.9.12. This is the default.
+	V2 = version.V2 // htmx 2.x, tested against 2.0.2.
+)
+
+// ErrUnsupported is reported for an attribute or option that doesn't exist in the chosen htmx version.
+var ErrUnsupported = version.ErrUnsupported
+
+// An Option configures an [HX] created by [NewHX], [NewTempl], [NewGomponents], [NewStringAttrs], [NewHTMLTemplate] or [FuncMap].
+type Option func(*options)
+
+// options are the settings shared by every attribute an HX builds.
+type options struct {
+	version    Version
+	onError    func(error)
+	dataPrefix bool
+}
+
+// defaultOptions target htmx 1.x, and ignore errors.
+func defaultOptions() options {
+	return options{
+		version:    V1,
+		onError:    nil,
+		dataPrefix: false,
+	}
+}
+
+// WithVersion renders attributes for a major version of htmx. The default is [V1].
+//
+// With [V2], [HX.On] uses the `hx-on::event` shorthand for htmx events, and htmx 2 features like [HX.Inherit] and the textContent swap are available.
+// Attributes that don't exist in the chosen version render nothing, and are reported to [OnError].
+//
+//	var hx = htmx.NewTempl(htmx.WithVersion(htmx.V2))
+func WithVersion(v Version) Option {
+	return func(o *options) {
+		o.version = v
+	}
+}
+
+// WithDataPrefix renders every attribute with the data- prefix, like data-hx-get, data-hx-on:click, data-sse-swap and data-classes.
+// htmx and its extensions accept both forms, and the prefixed form passes HTML validators and sanitizers that strip unknown attributes.
+//
+// This covers attributes from extensions and [HX.Attr] too, since they're all built by the HX.
+// Attributes that already start with data-, like those from the loading-states extension, are left as they are.
+//
+//	var hx = htmx.NewTempl(htmx.WithDataPrefix())
+func WithDataPrefix() Option {
+	return func(o *options) {
+		o.dataPrefix = true
+	}
+}
+
+// OnError sets a function to call when an attribute can't be rendered, like an attribute that doesn't exist in the chosen [Version],
+// an empty selector, or a value that can't be marshaled to JSON.
+// By default, these attributes silently render nothing.
+//
+// [PanicOnError] and [LogErrors] set common policies. Only the last of these options is used.
+// To return errors from a single render instead, use [HX.WithCollector].
+func OnError(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// PanicOnError panics when an attribute can't be rendered. This is useful in development and tests, to catch mistakes before they ship.
+//
+//	var hx = htmx.NewTempl(htmx.PanicOnError())
+func PanicOnError() Option {
+	return OnError(func(err error) {
+		panic(err)
+	})
+}
+
+// LogErrors logs attributes that can't be rendered to a logger, at the error level.
+//
+//	var hx = htmx.NewTempl(htmx.LogErrors(slog.Default()))
+func LogErrors(logger *slog.Logger) Option {
+	return OnError(func(err error) {
+		logger.Error("htmx: attribute not rendered", "error", err)
+	})
+}
+
+// withDataPrefix wraps an attribute constructor to add the data- prefix to every attribute.
+// The content attribute from [HX.Config] is for the htmx-config meta element, so it's left alone.
+func withDataPrefix[T any](attr NewAttr[T]) NewAttr[T] {
+	return func(key Attribute, value any) T {
+		if key != "content" && !strings.HasPrefix(string(key), "data-") {
+			key = "data-" + key
+		}
+		return attr(key, value)
+	}
+}
+
+// Version returns the major version of htmx this HX renders attributes for.
+func (hx *HX[T]) Version() Version {
+	return hx.opts.version
+}
+
+// report passes an error to the OnError function, if there is one.
+func (hx *HX[T]) report(err error) {
+	if hx.opts.onError != nil {
+		hx.opts.onError(err)
+	}
+}
+
+// Reject reports an attribute that can't be rendered to the [OnError] policy, and returns an empty attribute in its place.
+// Extensions use it for invalid input, so that every attribute is checked the same way.
+func (hx *HX[T]) Reject(err error) T {
+	hx.report(err)
+	var zero T
+	return zero
+}
@@ -0,0 +1,360 @@
+package htmx_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/classtools"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/loadingstates"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/preload"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/responsetargets"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/sse"
+	"github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+	"github.com/will-wow/typed-htmx-go/htmx/on"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+)
+
+var hx2 = htmx.NewStringAttrs(htmx.WithVersion(htmx.V2))
+
+func ExampleWithVersion() {
+	hx := htmx.NewStringAttrs(htmx.WithVersion(htmx.V2))
+	fmt.Println(hx.On(on.AfterRequest, "this.reset()"))
+	fmt.Println(hx.Swap(swap.TextContent))
+	// Output:
+	// hx-on::after-request='this.reset()'
+	// hx-swap='textContent'
+}
+
+func ExampleWithDataPrefix() {
+	hx := htmx.NewStringAttrs(htmx.WithDataPrefix())
+	fmt.Println(hx.Get("/search"))
+	fmt.Println(hx.On("click", "alert('hi')"))
+	fmt.Println(sse.Swap(hx, "message"))
+	// Output:
+	// data-hx-get='/search'
+	// data-hx-on:click='alert(&#39;hi&#39;)'
+	// data-sse-swap='message'
+}
+
+func ExampleOnError() {
+	hx := htmx.NewStringAttrs(htmx.OnError(func(err error) {
+		fmt.Println(err)
+	}))
+	fmt.Printf("%q\n", hx.InheritAll())
+	// Output:
+	// htmx: not supported by this version: hx-inherit is not supported by htmx 1.x
+	// ""
+}
+
+func ExamplePanicOnError() {
+	hx := htmx.NewStringAttrs(htmx.PanicOnError())
+	defer func() {
+		fmt.Println("panic:", recover())
+	}()
+	hx.Target("")
+	// Output: panic: htmx: invalid attribute value: hx-target: empty selector
+}
+
+func ExampleLogErrors() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		// Leave out the time, so the output is the same every run.
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	hx := htmx.NewStringAttrs(htmx.LogErrors(logger))
+	hx.Vals(map[string]any{"callback": func() {}})
+	// Output: level=ERROR msg="htmx: attribute not rendered" error="htmx: invalid attribute value: hx-vals: json: unsupported type: func()"
+}
+
+func ExampleHX_Inherit() {
+	fmt.Println(hx2.Inherit(htmx.Target, htmx.Select))
+	// Output: hx-inherit='hx-target hx-select'
+}
+
+func ExampleHX_InheritAll() {
+	fmt.Println(hx2.InheritAll())
+	// Output: hx-inherit='*'
+}
+
+func TestWithVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version htmx.Version
+		attr    func(hx htmx.HX[string]) string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "v1 htmx event",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return hx.On(on.AfterRequest, "done()") },
+			want:    "hx-on:htmx:after-request='done()'",
+		},
+		{
+			name:    "v2 dom event",
+			version: htmx.V2,
+			attr:    func(hx htmx.HX[string]) string { return hx.On("click", "done()") },
+			want:    "hx-on:click='done()'",
+		},
+		{
+			name:    "v2 nested htmx event",
+			version: htmx.V2,
+			attr:    func(hx htmx.HX[string]) string { return hx.On(on.ValidationHalted, "done()") },
+			want:    "hx-on::validation:halted='done()'",
+		},
+		{
+			name:    "v1 text content swap",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return hx.Swap(swap.TextContent) },
+			wantErr: true,
+		},
+		{
+			name:    "v1 extended text content swap",
+			version: htmx.V1,
+			attr: func(hx htmx.HX[string]) string {
+				return hx.SwapExtended(swap.New().Strategy(swap.TextContent).Transition())
+			},
+			wantErr: true,
+		},
+		{
+			name:    "v1 oob text content swap",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return hx.SwapOOBSelector(swap.TextContent, "#count") },
+			wantErr: true,
+		},
+		{
+			name:    "v1 select oob text content swap",
+			version: htmx.V1,
+			attr: func(hx htmx.HX[string]) string {
+				return hx.SelectOOBWithStrategy(htmx.SelectOOBStrategy{Selector: "#count", Strategy: swap.TextContent})
+			},
+			wantErr: true,
+		},
+		{
+			name:    "v2 oob text content swap",
+			version: htmx.V2,
+			attr:    func(hx htmx.HX[string]) string { return hx.SwapOOBWithStrategy(swap.TextContent) },
+			want:    "hx-swap-oob='textContent'",
+		},
+		{
+			name:    "v1 raw inherit",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return hx.Attr(htmx.Inherit, "*") },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr error
+			hx := htmx.NewStringAttrs(htmx.WithVersion(tt.version), htmx.OnError(func(err error) { gotErr = err }))
+
+			got := tt.attr(hx)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if tt.wantErr != errors.Is(gotErr, htmx.ErrUnsupported) {
+				t.Errorf("got error %v, want unsupported: %t", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithDataPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		version htmx.Version
+		attr    func(hx htmx.HX[string]) string
+		want    string
+	}{
+		{
+			name:    "attribute",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return hx.Target("#result") },
+			want:    "data-hx-target='#result'",
+		},
+		{
+			name:    "v2 htmx event",
+			version: htmx.V2,
+			attr:    func(hx htmx.HX[string]) string { return hx.On(on.AfterRequest, "done()") },
+			want:    "data-hx-on::after-request='done()'",
+		},
+		{
+			name:    "raw attribute",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return hx.Attr("hx-get", "/search") },
+			want:    "data-hx-get='/search'",
+		},
+		{
+			name:    "response target",
+			version: htmx.V1,
+			attr: func(hx htmx.HX[string]) string {
+				return responsetargets.Target(hx, responsetargets.Wildcard(4), "#errors")
+			},
+			want: "data-hx-target-4*='#errors'",
+		},
+		{
+			name:    "classes",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return classtools.Classes(hx, classtools.Add("foo", 0)) },
+			want:    "data-classes='add foo:0s'",
+		},
+		{
+			name:    "preload",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return preload.Preload(hx) },
+			want:    "data-preload",
+		},
+		{
+			name:    "already prefixed",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return loadingstates.DataLoadingClass(hx, "loading") },
+			want:    "data-loading-class='loading'",
+		},
+		{
+			name:    "config meta content",
+			version: htmx.V1,
+			attr:    func(hx htmx.HX[string]) string { return hx.Config(hxconfig.New().HistoryCacheSize(20)) },
+			want:    `content='{"historyCacheSize":20}'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hx := htmx.NewStringAttrs(htmx.WithVersion(tt.version), htmx.WithDataPrefix())
+			if got := tt.attr(hx); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHX_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		attr func(hx htmx.HX[string]) string
+		want string
+	}{
+		{
+			name: "empty target",
+			attr: func(hx htmx.HX[string]) string { return hx.Target("") },
+			want: "htmx: invalid attribute value: hx-target: empty selector",
+		},
+		{
+			name: "blank select",
+			attr: func(hx htmx.HX[string]) string { return hx.Select(" ") },
+			want: "htmx: invalid attribute value: hx-select: empty selector",
+		},
+		{
+			name: "no select oob selectors",
+			attr: func(hx htmx.HX[string]) string { return hx.SelectOOB() },
+			want: "htmx: invalid attribute value: hx-select-oob: empty selector",
+		},
+		{
+			name: "empty select oob selector",
+			attr: func(hx htmx.HX[string]) string { return hx.SelectOOB("#a", "") },
+			want: "htmx: invalid attribute value: hx-select-oob: empty selector",
+		},
+		{
+			name: "empty select oob strategy selector",
+			attr: func(hx htmx.HX[string]) string {
+				return hx.SelectOOBWithStrategy(htmx.SelectOOBStrategy{Selector: "", Strategy: swap.OuterHTML})
+			},
+			want: "htmx: invalid attribute value: hx-select-oob: empty selector",
+		},
+		{
+			name: "empty swap oob selector",
+			attr: func(hx htmx.HX[string]) string { return hx.SwapOOBSelector(swap.OuterHTML, "") },
+			want: "htmx: invalid attribute value: hx-swap-oob: empty selector",
+		},
+		{
+			name: "empty include",
+			attr: func(hx htmx.HX[string]) string { return hx.Include("") },
+			want: "htmx: invalid attribute value: hx-include: empty selector",
+		},
+		{
+			name: "empty indicator",
+			attr: func(hx htmx.HX[string]) string { return hx.Indicator("") },
+			want: "htmx: invalid attribute value: hx-indicator: empty selector",
+		},
+		{
+			name: "empty disabled elt",
+			attr: func(hx htmx.HX[string]) string { return hx.DisabledElt("") },
+			want: "htmx: invalid attribute value: hx-disabled-elt: empty selector",
+		},
+		{
+			name: "empty sync",
+			attr: func(hx htmx.HX[string]) string { return hx.SyncStrategy("", htmx.SyncAbort) },
+			want: "htmx: invalid attribute value: hx-sync: empty selector",
+		},
+		{
+			name: "threshold out of range",
+			attr: func(hx htmx.HX[string]) string {
+				return hx.TriggerExtended(trigger.On("click"), trigger.Intersect().Threshold(2))
+			},
+			want: `trigger: invalid hx-trigger value: threshold: "2" is not a number between 0.0 and 1.0`,
+		},
+		{
+			name: "zero poll",
+			attr: func(hx htmx.HX[string]) string { return hx.TriggerExtended(trigger.Every(0)) },
+			want: "trigger: invalid hx-trigger value: every: 0s is not a positive interval",
+		},
+		{
+			name: "unmarshalable headers",
+			attr: func(hx htmx.HX[string]) string { return hx.Headers(map[string]any{"ch": make(chan int)}) },
+			want: "htmx: invalid attribute value: hx-headers: json: unsupported type: chan int",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs htmx.ErrorCollector
+			hx := hx.WithCollector(&errs)
+
+			if got := tt.attr(hx); got != "" {
+				t.Errorf("got %q, want nothing rendered", got)
+			}
+			if err := errs.Err(); err == nil || err.Error() != tt.want {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHX_Config_version(t *testing.T) {
+	config := hxconfig.New().
+		UseTemplateFragments(true).
+		DisableInheritance(true).
+		ScrollBehavior(hxconfig.ScrollBehaviorInstant)
+
+	var errs []error
+	report := htmx.OnError(func(err error) { errs = append(errs, err) })
+
+	hx1 := htmx.NewStringAttrs(report)
+	v1 := hx1.Config(config)
+	if want := `content='{"useTemplateFragments":true}'`; v1 != want {
+		t.Errorf("got %s, want %s", v1, want)
+	}
+	want := "htmx: not supported by this version: config disableInheritance is not supported by htmx 1.x\n" +
+		`htmx: not supported by this version: config scrollBehavior "instant" is not supported by htmx 1.x`
+	if len(errs) != 1 || errs[0].Error() != want {
+		t.Errorf("got errors %v, want %q", errs, want)
+	}
+
+	errs = nil
+	hx2 := htmx.NewStringAttrs(htmx.WithVersion(htmx.V2), report)
+	v2 := hx2.Config(config)
+	// instant is the default scrollBehavior in htmx 2, so it's left out.
+	if want := `content='{"disableInheritance":true}'`; v2 != want {
+		t.Errorf("got %s, want %s", v2, want)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], htmx.ErrUnsupported) {
+		t.Errorf("expected useTemplateFragments to be unsupported in htmx 2, got %v", errs)
+	}
+}
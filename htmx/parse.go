@@ -0,0 +1,95 @@
+package htmx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+)
+
+// ErrInvalid is returned when parsing an invalid attribute value.
+var ErrInvalid = errors.New("htmx: invalid attribute value")
+
+// ParseRequestConfig parses a static hx-request value, like `"timeout":100,"credentials":true`, into a [RequestConfig].
+// The surrounding braces are optional. Runtime values that start with `js:` or `javascript:` are not supported.
+//
+// The config's String method returns the canonical form of the value, with false options removed.
+func ParseRequestConfig(s string) (RequestConfig, error) {
+	config := RequestConfig{}
+
+	value := strings.TrimSpace(s)
+	if strings.HasPrefix(value, "js:") || strings.HasPrefix(value, "javascript:") {
+		return config, fmt.Errorf("%w: hx-request %q uses javascript, which can't be parsed", ErrInvalid, s)
+	}
+	if value == "" {
+		return config, nil
+	}
+	if !strings.HasPrefix(value, "{") {
+		value = "{" + value + "}"
+	}
+
+	var options map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &options); err != nil {
+		return config, fmt.Errorf("%w: hx-request %q: %s", ErrInvalid, s, err)
+	}
+
+	for key, raw := range options {
+		var err error
+		switch key {
+		case "timeout":
+			var ms float64
+			err = json.Unmarshal(raw, &ms)
+			config.Timeout = time.Duration(ms * float64(time.Millisecond))
+		case "credentials":
+			err = unmarshalBool(raw, &config.Credentials)
+		case "noHeaders":
+			err = unmarshalBool(raw, &config.NoHeaders)
+		default:
+			err = errors.New("unknown option")
+		}
+		if err != nil {
+			return config, fmt.Errorf("%w: hx-request %q: %s: %s", ErrInvalid, s, key, err)
+		}
+	}
+
+	return config, nil
+}
+
+// unmarshalBool decodes a JSON boolean, rejecting null.
+func unmarshalBool(raw json.RawMessage, b *bool) error {
+	if bytes.Equal(raw, []byte("null")) {
+		return errors.New("expected a boolean")
+	}
+	return json.Unmarshal(raw, b)
+}
+
+// ParseSelectOOB parses an hx-select-oob value, like `#alert:afterbegin,#info`, into the selectors and optional strategies for [HX.SelectOOBWithStrategy].
+func ParseSelectOOB(s string) ([]SelectOOBStrategy, error) {
+	parts := strings.Split(s, ",")
+	selectors := make([]SelectOOBStrategy, len(parts))
+
+	for i, part := range parts {
+		selector, strategy, found := strings.Cut(strings.TrimSpace(part), ":")
+		if selector == "" {
+			return nil, fmt.Errorf("%w: hx-select-oob %q: missing selector", ErrInvalid, s)
+		}
+
+		selectors[i] = SelectOOBStrategy{
+			Selector: StandardCSSSelector(selector),
+			Strategy: "",
+		}
+		if found {
+			parsed, err := swap.ParseStrategy(strategy)
+			if err != nil {
+				return nil, fmt.Errorf("%w: hx-select-oob %q: %s", ErrInvalid, s, err)
+			}
+			selectors[i].Strategy = parsed
+		}
+	}
+
+	return selectors, nil
+}
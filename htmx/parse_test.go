@@ -0,0 +1,63 @@
+package htmx_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+)
+
+func ExampleParseRequestConfig() {
+	config, _ := htmx.ParseRequestConfig(`"timeout": 500, "credentials": true, "noHeaders": false`)
+	fmt.Println(hx.Request(config))
+	// Output: hx-request='"timeout":500,"credentials":true'
+}
+
+func ExampleParseSelectOOB() {
+	selectors, _ := htmx.ParseSelectOOB("#alert:afterbegin, #info")
+	fmt.Println(hx.SelectOOBWithStrategy(selectors...))
+	// Output: hx-select-oob='#alert:afterbegin,#info'
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func() error
+		want  string
+	}{
+		{
+			name:  "javascript request",
+			parse: func() error { _, err := htmx.ParseRequestConfig("js: timeout:getTimeout()"); return err },
+			want:  `htmx: invalid attribute value: hx-request "js: timeout:getTimeout()" uses javascript, which can't be parsed`,
+		},
+		{
+			name:  "unknown request option",
+			parse: func() error { _, err := htmx.ParseRequestConfig(`{"retries":2}`); return err },
+			want:  `htmx: invalid attribute value: hx-request "{\"retries\":2}": retries: unknown option`,
+		},
+		{
+			name:  "wrong request type",
+			parse: func() error { _, err := htmx.ParseRequestConfig(`"credentials":"yes"`); return err },
+			want:  `htmx: invalid attribute value: hx-request "\"credentials\":\"yes\"": credentials: json: cannot unmarshal string into Go value of type bool`,
+		},
+		{
+			name:  "missing oob selector",
+			parse: func() error { _, err := htmx.ParseSelectOOB("#alert,:afterbegin"); return err },
+			want:  `htmx: invalid attribute value: hx-select-oob "#alert,:afterbegin": missing selector`,
+		},
+		{
+			name:  "unknown oob strategy",
+			parse: func() error { _, err := htmx.ParseSelectOOB("#alert:prepend"); return err },
+			want:  `htmx: invalid attribute value: hx-select-oob "#alert:prepend": swap: invalid hx-swap value: unknown strategy "prepend"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parse()
+			if !errors.Is(err, htmx.ErrInvalid) || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
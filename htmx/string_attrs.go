@@ -1,21 +1,24 @@
 package htmx
 
-import "fmt"
+import "strings"
 
 // NewStringAttrs returns a HX instance that returns stringified attributes for direct use in HTML.
-func NewStringAttrs() HX[string] {
+// Values are single-quoted, with ampersands and single quotes escaped.
+// Values that can't be rendered, like unsupported types, return an empty string.
+func NewStringAttrs(opts ...Option) HX[string] {
 	return NewHX(func(k Attribute, v any) string {
-		switch v := v.(type) {
-		// For strings, print the key='value' pair.
-		case string:
-			return fmt.Sprintf(`%s='%v'`, k, v)
+		value, ok, err := attrValue(v)
+		if err != nil || !ok {
+			return ""
+		}
 		// For booleans, print just the key if true.
-		case bool:
-			if v {
-				return string(k)
-			}
+		if value == nil {
+			return string(k)
 		}
-
-		return ""
-	})
+		// Otherwise print the key='value' pair.
+		return string(k) + `='` + singleQuoteEscaper.Replace(*value) + `'`
+	}, opts...)
 }
+
+// singleQuoteEscaper escapes the characters that aren't safe in a single-quoted attribute value.
+var singleQuoteEscaper = strings.NewReplacer(`&`, "&amp;", `'`, "&#39;")
@@ -2,9 +2,13 @@
 package swap
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/will-wow/typed-htmx-go/htmx/internal/interval"
 	"github.com/will-wow/typed-htmx-go/htmx/internal/mod"
 	"github.com/will-wow/typed-htmx-go/htmx/internal/util"
 )
@@ -58,6 +62,7 @@ const (
 	AfterEnd    Strategy = "afterend"    // Insert the response after the target element
 	Delete      Strategy = "delete"      // Deletes the target element regardless of the response
 	None        Strategy = "none"        // Does not append content from response (out of band items will still be processed).
+	TextContent Strategy = "textContent" // Replace the text content of the target element, without parsing the response as HTML. Requires htmx 2.x.
 )
 
 // Strategy allows you to specify how the response will be swapped in relative to the target of an AJAX request. If you do not specify the option, the default is htmx.config.defaultSwapStyle (innerHTML).
@@ -158,3 +163,81 @@ func (s *Builder) Clear(modifier Modifier) *Builder {
 	delete(s.modifiers, modifier)
 	return s
 }
+
+// ErrInvalid is returned when parsing an invalid hx-swap value.
+var ErrInvalid = errors.New("swap: invalid hx-swap value")
+
+// strategies are the swap strategies known to htmx.
+var strategies = []Strategy{InnerHTML, OuterHTML, BeforeBegin, AfterBegin, BeforeEnd, AfterEnd, Delete, None, TextContent}
+
+// ParseStrategy parses a swap strategy, like "outerHTML".
+// Strategies added by extensions are not supported.
+func ParseStrategy(s string) (Strategy, error) {
+	if slices.Contains(strategies, Strategy(s)) {
+		return Strategy(s), nil
+	}
+	return "", fmt.Errorf("%w: unknown strategy %q", ErrInvalid, s)
+}
+
+// Parse parses an hx-swap attribute value, or an HX-Reswap header, into a builder.
+// If the value starts with a modifier, the strategy defaults to innerHTML, like htmx.
+//
+// The builder's String method returns the canonical form of the value, with an explicit strategy and sorted modifiers.
+func Parse(s string) (*Builder, error) {
+	b := New()
+
+	fields := strings.Fields(s)
+	if len(fields) > 0 && !strings.Contains(fields[0], ":") {
+		strategy, err := ParseStrategy(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		b.Strategy(strategy)
+		fields = fields[1:]
+	}
+
+	for _, field := range fields {
+		name, value, _ := strings.Cut(field, ":")
+		if err := b.parseModifier(Modifier(name), value); err != nil {
+			return nil, fmt.Errorf("%w: %s in %q", ErrInvalid, err, s)
+		}
+	}
+
+	return b, nil
+}
+
+// parseModifier validates and sets a single modifier.
+func (s *Builder) parseModifier(modifier Modifier, value string) error {
+	switch modifier {
+	case Transition, IgnoreTitle, FocusScroll:
+		on, err := util.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", modifier, err)
+		}
+		s.modifiers[modifier] = util.BoolToString(on)
+	case Swap, Settle:
+		wait, err := interval.Parse(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", modifier, err)
+		}
+		s.modifiers[modifier] = wait.String()
+	case Scroll, Show:
+		if modifier == Show && value == "none" {
+			s.ShowNone()
+			return nil
+		}
+		// Selectors can contain colons, like #a:hover, so the direction is after the last one.
+		selector, direction, _ := util.CutLast(value, ":")
+		if direction != string(Top) && direction != string(Bottom) {
+			return fmt.Errorf("%s: invalid direction %q", modifier, direction)
+		}
+		if selector == "" {
+			s.modifiers[modifier] = direction
+		} else {
+			s.modifiers[modifier] = fmt.Sprintf("%s:%s", selector, direction)
+		}
+	default:
+		return fmt.Errorf("unknown modifier %q", modifier)
+	}
+	return nil
+}
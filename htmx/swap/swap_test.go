@@ -1,7 +1,9 @@
 package swap_test
 
 import (
+	"errors"
 	"fmt"
+	"testing"
 	"time"
 
 	"github.com/will-wow/typed-htmx-go/htmx"
@@ -114,3 +116,63 @@ func ExampleBuilder_Clear() {
 	)
 	// output: hx-swap='innerHTML'
 }
+
+func ExampleParse() {
+	builder, _ := swap.Parse("outerHTML settle:500ms scroll:#list:bottom")
+	fmt.Println(hx.SwapExtended(builder.Transition()))
+	// output: hx-swap='outerHTML scroll:#list:bottom settle:500ms transition:true'
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: "innerHTML"},
+		{value: "outerHTML", want: "outerHTML"},
+		{value: "settle:1s", want: "innerHTML settle:1s"},
+		{value: "beforeend swap:100 settle:0.5s", want: "beforeend settle:500ms swap:100ms"},
+		{value: "  none\ttransition:false ignoreTitle:true focus-scroll:true ", want: "none focus-scroll:true ignoreTitle:true transition:false"},
+		{value: "innerHTML scroll:top show:window:bottom", want: "innerHTML scroll:top show:window:bottom"},
+		{value: "innerHTML show:none", want: "innerHTML show:none"},
+		{value: "show:#a:hover:top", want: "innerHTML show:#a:hover:top"},
+		{value: "scroll:li:first-child:bottom", want: "innerHTML scroll:li:first-child:bottom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			builder, err := swap.Parse(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := builder.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			again, err := swap.Parse(tt.want)
+			if err != nil || again.String() != tt.want {
+				t.Errorf("canonical value %q did not round-trip: %q, %v", tt.want, again, err)
+			}
+		})
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "outerHtml", want: `swap: invalid hx-swap value: unknown strategy "outerHtml"`},
+		{value: "innerHTML settle:soon", want: `swap: invalid hx-swap value: settle: invalid timing "soon" in "innerHTML settle:soon"`},
+		{value: "innerHTML transition:yes", want: `swap: invalid hx-swap value: transition: invalid boolean "yes" in "innerHTML transition:yes"`},
+		{value: "innerHTML scroll:#list:middle", want: `swap: invalid hx-swap value: scroll: invalid direction "middle" in "innerHTML scroll:#list:middle"`},
+		{value: "innerHTML wait:1s", want: `swap: invalid hx-swap value: unknown modifier "wait" in "innerHTML wait:1s"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, err := swap.Parse(tt.value)
+			if !errors.Is(err, swap.ErrInvalid) || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,25 +1,50 @@
 package htmx
 
-import "github.com/a-h/templ"
+import (
+	"slices"
+
+	"github.com/a-h/templ"
+)
 
 // NewTempl returns a HX instance for use with Templ.
 // Each attribute returns a templ.Attributes map, which can be spread into a templ element.
-func NewTempl() HX[templ.Attributes] {
+func NewTempl(opts ...Option) HX[templ.Attributes] {
 	return NewHX(
 		func(key Attribute, value any) templ.Attributes {
 			return templ.Attributes{string(key): value}
 		},
+		opts...,
 	)
 }
 
 // TemplAttrs merges the given attributes into a single map.
 // This is helpful for passing many attributes to a templ component.
+//
+// Attributes set more than once are merged by their meaning, so hx-ext lists are combined, hx-on handlers for the same event both run,
+// and hx-vals and hx-headers are deep-merged. For other attributes, the last value wins. Use [MergeTemplAttrs] to find those conflicts.
 func TemplAttrs(attrs ...templ.Attributes) templ.Attributes {
-	out := templ.Attributes{}
+	out, _ := MergeTemplAttrs(attrs...)
+	return out
+}
+
+// MergeTemplAttrs merges the given attributes like [TemplAttrs], and returns an error wrapping [ErrConflict] for each attribute set to different values that can't be combined, like two hx-target attributes.
+func MergeTemplAttrs(attrs ...templ.Attributes) (templ.Attributes, error) {
+	var all []mergedAttr
 	for _, a := range attrs {
-		for k, v := range a {
-			out[k] = v
+		keys := make([]string, 0, len(a))
+		for k := range a {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			all = append(all, mergedAttr{key: k, value: a[k]})
 		}
 	}
-	return out
+
+	merged, err := mergeAttrs(all)
+	out := make(templ.Attributes, len(merged))
+	for _, attr := range merged {
+		out[attr.key] = attr.value
+	}
+	return out, err
 }
@@ -58,6 +58,25 @@ func (e *Event) String() string {
 	return mod.Join(e.coreEvent(), e.modifiers)
 }
 
+// Validate returns an error wrapping [ErrInvalid] if the event has no name, a selector modifier is empty, or the intersection threshold is outside 0.0 to 1.0.
+func (e *Event) Validate() error {
+	if e.event == "" {
+		return fmt.Errorf("%w: missing event name", ErrInvalid)
+	}
+	for _, modifier := range []Modifier{From, Target, Root} {
+		if selector, ok := e.modifiers[modifier]; ok && (selector == "" || selector == "()") {
+			return fmt.Errorf("%w: %s: missing selector", ErrInvalid, modifier)
+		}
+	}
+	if value, ok := e.modifiers[Threshold]; ok {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			return fmt.Errorf("%w: threshold: %q is not a number between 0.0 and 1.0", ErrInvalid, value)
+		}
+	}
+	return nil
+}
+
 // OverrideEvent overrides the initial event name. This is useful if you are forking a default trigger setup.
 func (e *Event) OverrideEvent(event TriggerEvent) *Event {
 	e.event = event
@@ -233,7 +252,8 @@ func (e *IntersectEvent) Root(selector string) *IntersectEvent {
 	return e
 }
 
-// Threshold takes a floating point number between 0.0 and 1.0, indicating what amount of intersection to fire the event on
+// Threshold takes a floating point number between 0.0 and 1.0, indicating what amount of intersection to fire the event on.
+// Other values are reported by [Event.Validate].
 func (e *IntersectEvent) Threshold(threshold float64) *IntersectEvent {
 	e.modifiers[Threshold] = strconv.FormatFloat(threshold, 'f', -1, 64)
 	return e
@@ -0,0 +1,225 @@
+package trigger
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/will-wow/typed-htmx-go/htmx/internal/interval"
+)
+
+// ErrInvalid is returned when parsing an invalid hx-trigger value.
+var ErrInvalid = errors.New("trigger: invalid hx-trigger value")
+
+// queueOptions are the valid values of the queue modifier.
+var queueOptions = []QueueOption{First, Last, All, None}
+
+// Parse parses an hx-trigger attribute value into a list of triggers, which can be passed to [htmx.HX.TriggerExtended].
+// Each trigger is an [*Event], an [*IntersectEvent] for the intersect event, or a [*Poll].
+//
+// The triggers' String methods return the canonical form of the value, with sorted modifiers and disambiguated selectors.
+func Parse(s string) ([]Trigger, error) {
+	parts := split(s, ',')
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("%w: empty value", ErrInvalid)
+	}
+
+	triggers := make([]Trigger, len(parts))
+	for i, part := range parts {
+		t, err := parseTrigger(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s in %q", ErrInvalid, err, s)
+		}
+		triggers[i] = t
+	}
+	return triggers, nil
+}
+
+// parseTrigger parses a single trigger from a comma-separated list.
+func parseTrigger(s string) (Trigger, error) {
+	if rest, ok := strings.CutPrefix(s, "every"); ok && rest != "" && isSpace(rest[0]) {
+		return parsePoll(strings.TrimSpace(rest))
+	}
+
+	name, filter, rest, err := cutEvent(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var e *Event
+	var intersect *IntersectEvent
+	if name == "intersect" {
+		intersect = Intersect()
+		e = &intersect.Event
+	} else {
+		e = On(TriggerEvent(name))
+	}
+	e.When(filter)
+
+	tokens := split(rest, ' ')
+	for i := 0; i < len(tokens); i++ {
+		modifier, value, hasValue := strings.Cut(tokens[i], ":")
+
+		switch Modifier(modifier) {
+		case Once, Changed, Consume:
+			if hasValue {
+				return nil, fmt.Errorf("%s does not take a value", modifier)
+			}
+			e.modifiers[Modifier(modifier)] = ""
+		case Delay, Throttle:
+			timing, err := interval.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", modifier, err)
+			}
+			e.modifiers[Modifier(modifier)] = timing.String()
+		case From:
+			if value == "" {
+				return nil, errors.New("from: missing selector")
+			}
+			relative := slices.Contains([]SelectorModifier{Closest, Find, Next, Previous}, SelectorModifier(value))
+			if relative && i+1 < len(tokens) && !isModifier(tokens[i+1]) {
+				i++
+				e.From(FromRelative(SelectorModifier(value), unwrap(tokens[i])))
+			} else if relative && (value == string(Closest) || value == string(Find)) {
+				return nil, fmt.Errorf("from: missing selector after %q", value)
+			} else {
+				e.From(FromSelector(unwrap(value)))
+			}
+		case Target:
+			if value == "" {
+				return nil, errors.New("target: missing selector")
+			}
+			e.Target(unwrap(value))
+		case Queue:
+			if !slices.Contains(queueOptions, QueueOption(value)) {
+				return nil, fmt.Errorf("queue: invalid option %q", value)
+			}
+			e.Queue(QueueOption(value))
+		case Root, Threshold:
+			if intersect == nil {
+				return nil, fmt.Errorf("%s is only supported by the intersect event", modifier)
+			}
+			if Modifier(modifier) == Root {
+				if value == "" {
+					return nil, errors.New("root: missing selector")
+				}
+				intersect.Root(unwrap(value))
+				continue
+			}
+			threshold, err := strconv.ParseFloat(value, 64)
+			if err != nil || threshold < 0 || threshold > 1 {
+				return nil, fmt.Errorf("threshold: %q is not a number between 0.0 and 1.0", value)
+			}
+			intersect.Threshold(threshold)
+		default:
+			return nil, fmt.Errorf("unknown modifier %q", modifier)
+		}
+	}
+
+	if intersect != nil {
+		return intersect, nil
+	}
+	return e, nil
+}
+
+// parsePoll parses the timing and optional filter of an "every" trigger.
+func parsePoll(s string) (*Poll, error) {
+	timing, filter, _ := strings.Cut(s, "[")
+	timing = strings.TrimSpace(timing)
+
+	d, err := interval.Parse(timing)
+	if err != nil {
+		return nil, fmt.Errorf("every: %w", err)
+	}
+	p := Every(d)
+
+	if filter != "" {
+		filter, ok := strings.CutSuffix(strings.TrimSpace(filter), "]")
+		if !ok {
+			return nil, errors.New("every: unclosed filter")
+		}
+		p.Filter(filter)
+	}
+	return p, nil
+}
+
+// cutEvent splits an event name and its optional [filter] from its modifiers.
+func cutEvent(s string) (name string, filter string, rest string, err error) {
+	end := strings.IndexAny(s, "[ \t\r\n")
+	if end == -1 {
+		return s, "", "", nil
+	}
+	name = s[:end]
+	if name == "" {
+		return "", "", "", errors.New("missing event name")
+	}
+	if isSpace(s[end]) {
+		return name, "", s[end:], nil
+	}
+
+	depth := 0
+	for i := end; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return name, s[end+1 : i], s[i+1:], nil
+			}
+		}
+	}
+	return "", "", "", fmt.Errorf("unclosed filter on %q", name)
+}
+
+// isModifier checks if a token starts a new modifier.
+func isModifier(token string) bool {
+	name, _, _ := strings.Cut(token, ":")
+	switch Modifier(name) {
+	case Once, Changed, Delay, Throttle, From, Target, Consume, Queue, Root, Threshold:
+		return true
+	default:
+		return false
+	}
+}
+
+// unwrap removes the parentheses used to disambiguate a selector.
+func unwrap(selector string) string {
+	if strings.HasPrefix(selector, "(") && strings.HasSuffix(selector, ")") {
+		return selector[1 : len(selector)-1]
+	}
+	return selector
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// split splits a value on a separator, where a space matches any whitespace, ignoring separators inside brackets or parentheses, and drops empty parts.
+func split(s string, sep byte) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			switch s[i] {
+			case '[', '(':
+				depth++
+				continue
+			case ']', ')':
+				depth--
+				continue
+			}
+			if depth > 0 || !(s[i] == sep || sep == ' ' && isSpace(s[i])) {
+				continue
+			}
+		}
+		if part := strings.TrimSpace(s[start:i]); part != "" {
+			parts = append(parts, part)
+		}
+		start = i + 1
+	}
+	return parts
+}
@@ -30,6 +30,14 @@ func (p *Poll) String() string {
 	return fmt.Sprintf("every %s", p.timing.String())
 }
 
+// Validate returns an error wrapping [ErrInvalid] if the polling interval isn't positive.
+func (p *Poll) Validate() error {
+	if p.timing <= 0 {
+		return fmt.Errorf("%w: every: %s is not a positive interval", ErrInvalid, p.timing)
+	}
+	return nil
+}
+
 // Filter adds a filter to the polling trigger, so that when the timer goes off, the trigger will only occur if the expression evaluates to true.
 func (p *Poll) Filter(filter string) *Poll {
 	p.filter = filter
@@ -4,5 +4,7 @@ package trigger
 // A Trigger is a builder for hx-trigger attribute values.
 type Trigger interface {
 	String() string
+	// Validate returns an error wrapping [ErrInvalid] if the trigger would render an hx-trigger value htmx can't use.
+	Validate() error
 	trigger()
 }
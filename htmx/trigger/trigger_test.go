@@ -1,7 +1,11 @@
 package trigger_test
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"testing"
 	"time"
 
 	"github.com/will-wow/typed-htmx-go/htmx/trigger"
@@ -156,3 +160,118 @@ func ExampleEvent_OverrideEvent() {
 	fmt.Println(trig.String())
 	// Output: input delay:1s
 }
+
+func ExampleParse() {
+	triggers, _ := trigger.Parse("keyup[key=='Enter'] changed delay:500ms from:closest form, every 2s [isActive()]")
+	for _, t := range triggers {
+		fmt.Println(t.String())
+	}
+	// Output:
+	// keyup[key=='Enter'] changed delay:500ms from:closest (form)
+	// every 2s [isActive()]
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "click", want: []string{"click"}},
+		{value: "click, load", want: []string{"click", "load"}},
+		{value: "click[ctrlKey && shiftKey] once consume", want: []string{"click[ctrlKey && shiftKey] consume once"}},
+		{value: "input changed delay:1000 throttle:0.5s queue:all", want: []string{"input changed delay:1s queue:all throttle:500ms"}},
+		{value: "keyup from:body target:(#a .b)", want: []string{"keyup from:(body) target:(#a .b)"}},
+		{value: "keyup from:(closest form) delay:1s", want: []string{"keyup delay:1s from:(closest form)"}},
+		{value: "keyup from:next delay:1s", want: []string{"keyup delay:1s from:(next)"}},
+		{value: "keyup from:next .item", want: []string{"keyup from:next (.item)"}},
+		{value: "intersect once root:#main threshold:0.5", want: []string{"intersect once root:#main threshold:0.5"}},
+		{value: "every 1s", want: []string{"every 1s"}},
+		{value: "every 100ms [a, b], click", want: []string{"every 100ms [a, b]", "click"}},
+		{value: "\n\tclick\n\tonce\n", want: []string{"click once"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := parseStrings(t, tt.value)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			again := parseStrings(t, strings.Join(tt.want, ", "))
+			if !slices.Equal(again, tt.want) {
+				t.Errorf("canonical value %q did not round-trip: %q", tt.want, again)
+			}
+		})
+	}
+}
+
+func parseStrings(t *testing.T, value string) []string {
+	t.Helper()
+	triggers, err := trigger.Parse(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make([]string, len(triggers))
+	for i, trig := range triggers {
+		values[i] = trig.String()
+	}
+	return values
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: `trigger: invalid hx-trigger value: empty value`},
+		{value: "click[ctrlKey", want: `trigger: invalid hx-trigger value: unclosed filter on "click" in "click[ctrlKey"`},
+		{value: "click once:true", want: `trigger: invalid hx-trigger value: once does not take a value in "click once:true"`},
+		{value: "click delay:later", want: `trigger: invalid hx-trigger value: delay: invalid timing "later" in "click delay:later"`},
+		{value: "click queue:some", want: `trigger: invalid hx-trigger value: queue: invalid option "some" in "click queue:some"`},
+		{value: "click threshold:0.5", want: `trigger: invalid hx-trigger value: threshold is only supported by the intersect event in "click threshold:0.5"`},
+		{value: "intersect threshold:2", want: `trigger: invalid hx-trigger value: threshold: "2" is not a number between 0.0 and 1.0 in "intersect threshold:2"`},
+		{value: "click from:closest", want: `trigger: invalid hx-trigger value: from: missing selector after "closest" in "click from:closest"`},
+		{value: "click debounce:1s", want: `trigger: invalid hx-trigger value: unknown modifier "debounce" in "click debounce:1s"`},
+		{value: "every soon", want: `trigger: invalid hx-trigger value: every: invalid timing "soon" in "every soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, err := trigger.Parse(tt.value)
+			if !errors.Is(err, trigger.ErrInvalid) || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrigger_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger trigger.Trigger
+		want    string
+	}{
+		{name: "event", trigger: trigger.On("click").From("body").Delay(time.Second), want: ""},
+		{name: "intersect", trigger: trigger.Intersect().Root("#main").Threshold(0.5), want: ""},
+		{name: "poll", trigger: trigger.Every(time.Second), want: ""},
+		{name: "missing event", trigger: trigger.On(""), want: `trigger: invalid hx-trigger value: missing event name`},
+		{name: "empty from", trigger: trigger.On("click").From(""), want: `trigger: invalid hx-trigger value: from: missing selector`},
+		{name: "empty target", trigger: trigger.On("click").Target(""), want: `trigger: invalid hx-trigger value: target: missing selector`},
+		{name: "empty root", trigger: trigger.Intersect().Root(""), want: `trigger: invalid hx-trigger value: root: missing selector`},
+		{name: "threshold above 1", trigger: trigger.Intersect().Threshold(1.5), want: `trigger: invalid hx-trigger value: threshold: "1.5" is not a number between 0.0 and 1.0`},
+		{name: "negative threshold", trigger: trigger.Intersect().Threshold(-0.1), want: `trigger: invalid hx-trigger value: threshold: "-0.1" is not a number between 0.0 and 1.0`},
+		{name: "zero interval", trigger: trigger.Every(0), want: `trigger: invalid hx-trigger value: every: 0s is not a positive interval`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.trigger.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("got %v, want no error", err)
+				}
+				return
+			}
+			if !errors.Is(err, trigger.ErrInvalid) || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
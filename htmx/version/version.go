@@ -0,0 +1,29 @@
+// package version identifies the major htmx version that attributes and config are rendered for.
+// Use it through [htmx.WithVersion], which re-exports the versions as [htmx.V1] and [htmx.V2].
+package version
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A Version is a major version of htmx.
+type Version int
+
+const (
+	V1 Version = 1 // htmx 1.x, tested against 1.9.12. This is the default.
+	V2 Version = 2 // htmx 2.x, tested against 2.0.2.
+)
+
+// String returns the version as it's written in the htmx docs, like "htmx 2.x".
+func (v Version) String() string {
+	return fmt.Sprintf("htmx %d.x", int(v))
+}
+
+// ErrUnsupported is returned for an attribute, option or config key that doesn't exist in the chosen version.
+var ErrUnsupported = errors.New("htmx: not supported by this version")
+
+// Unsupported returns an error for a feature that doesn't exist in a version.
+func Unsupported(feature string, v Version) error {
+	return fmt.Errorf("%w: %s is not supported by %s", ErrUnsupported, feature, v)
+}
@@ -0,0 +1,121 @@
+package lint
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// scanGo finds raw attributes in Go source: calls like `g.Attr("hx-get", "/")` and `h.Data("hx-get", "/")`, and keys of templ.Attributes literals.
+// Generated files, like the _templ.go output of templ, are skipped.
+func scanGo(filename string, src []byte) ([]attribute, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if ast.IsGenerated(file) {
+		return nil, nil
+	}
+
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	source := func(node ast.Node) string {
+		return string(src[offset(node.Pos()):offset(node.End())])
+	}
+
+	var attrs []attribute
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			// Attribute getters, like goquery's Attr(name), take a single argument.
+			fn := funcName(node.Fun)
+			if len(node.Args) != 2 || (fn != "Attr" && fn != "Data") {
+				return true
+			}
+			name, ok := stringLit(node.Args[0])
+			if !ok {
+				return true
+			}
+			if fn == "Data" {
+				name = "data-" + name
+			}
+			if _, ok := Normalize(name); !ok {
+				return true
+			}
+
+			attrs = append(attrs, attribute{
+				name:        name,
+				value:       goValue(node.Args[1], source),
+				start:       offset(node.Pos()),
+				end:         offset(node.End()),
+				replaceable: true,
+			})
+		case *ast.CompositeLit:
+			typ, ok := node.Type.(*ast.SelectorExpr)
+			if !ok || typ.Sel.Name != "Attributes" {
+				return true
+			}
+			if pkg, ok := typ.X.(*ast.Ident); !ok || pkg.Name != "templ" {
+				return true
+			}
+			for _, elt := range node.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				name, ok := stringLit(kv.Key)
+				if !ok {
+					continue
+				}
+				if _, ok := Normalize(name); !ok {
+					continue
+				}
+				attrs = append(attrs, attribute{
+					name:        name,
+					value:       goValue(kv.Value, source),
+					start:       offset(kv.Pos()),
+					end:         offset(kv.End()),
+					replaceable: false,
+				})
+			}
+		}
+		return true
+	})
+	return attrs, nil
+}
+
+// funcName returns the name of a called function, which may be qualified or dot-imported.
+func funcName(fun ast.Expr) string {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	default:
+		return ""
+	}
+}
+
+// stringLit returns the value of a string literal.
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// goValue returns the value of an attribute set in Go. Literal strings and booleans can be checked, anything else is an expression.
+func goValue(expr ast.Expr, source func(ast.Node) string) Value {
+	if s, ok := stringLit(expr); ok {
+		return Value{Text: s, Expr: false, Present: true}
+	}
+	if ident, ok := expr.(*ast.Ident); ok && (ident.Name == "true" || ident.Name == "false") {
+		return Value{Text: ident.Name, Expr: false, Present: true}
+	}
+	return Value{Text: source(expr), Expr: true, Present: true}
+}
@@ -0,0 +1,158 @@
+// Package lint finds raw htmx attributes in templ and Go source files, checks their values, and suggests the typed calls that replace them.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A Rule identifies the kind of problem a [Finding] reports.
+type Rule string
+
+const (
+	RawAttribute     Rule = "raw-attribute"     // A valid attribute that should use the typed API.
+	InvalidValue     Rule = "invalid-value"     // An attribute with a value htmx can't parse.
+	UnknownAttribute Rule = "unknown-attribute" // An hx-* attribute that htmx doesn't define, usually a typo.
+)
+
+// A Finding is a raw attribute found in a source file.
+type Finding struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Start     int    `json:"start"` // Byte offset of the start of the attribute, or the Go call that sets it.
+	End       int    `json:"end"`   // Byte offset of the end of the attribute.
+	Attribute string `json:"attribute"`
+	Value     string `json:"value"`
+	Rule      Rule   `json:"rule"`
+	Message   string `json:"message"`
+	// Suggestion is the Go expression for the typed equivalent, if there is one.
+	Suggestion string `json:"suggestion,omitempty"`
+	// Replacement is the source that replaces the attribute between Start and End, if it can be replaced in place.
+	Replacement string `json:"replacement,omitempty"`
+	// Imports are the import paths the suggestion needs.
+	Imports []string `json:"imports,omitempty"`
+}
+
+// String formats the finding like a compiler error, with the suggested replacement.
+func (f Finding) String() string {
+	msg := fmt.Sprintf("%s:%d:%d: %s", f.File, f.Line, f.Column, f.Message)
+	switch {
+	case f.Replacement != "":
+		msg += fmt.Sprintf(" (suggest: %s)", f.Replacement)
+	case f.Suggestion != "":
+		msg += fmt.Sprintf(" (suggest: %s)", f.Suggestion)
+	}
+	return msg
+}
+
+// attribute is a raw attribute found by a scanner.
+type attribute struct {
+	name        string
+	value       Value
+	start       int
+	end         int
+	replaceable bool // The attribute can be replaced in place by a typed call.
+}
+
+// IsSource reports whether a file is a templ or Go source file that [File] can check.
+func IsSource(filename string) bool {
+	ext := filepath.Ext(filename)
+	return ext == ".templ" || ext == ".go"
+}
+
+// File checks the raw attributes in a .templ or .go file.
+func File(filename string, src []byte) ([]Finding, error) {
+	switch filepath.Ext(filename) {
+	case ".templ":
+		return Templ(filename, src), nil
+	case ".go":
+		return Go(filename, src)
+	default:
+		return nil, fmt.Errorf("lint: unsupported file type %q", filename)
+	}
+}
+
+// Templ checks the raw attributes in a templ file. Replacements are spread expressions, like `{ hx.Get("/")... }`.
+func Templ(filename string, src []byte) []Finding {
+	findings := check(filename, src, scanTempl(src))
+	for i, f := range findings {
+		if f.Replacement != "" {
+			findings[i].Replacement = fmt.Sprintf("{ %s... }", f.Replacement)
+		}
+	}
+	return findings
+}
+
+// Go checks the raw attributes set in Go source, for instance by gomponents.
+func Go(filename string, src []byte) ([]Finding, error) {
+	attrs, err := scanGo(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	return check(filename, src, attrs), nil
+}
+
+// check turns scanned attributes into findings, sorted by position.
+func check(filename string, src []byte, attrs []attribute) []Finding {
+	findings := make([]Finding, 0, len(attrs))
+	for _, a := range attrs {
+		line, column := position(src, a.start)
+		f := Finding{
+			File:        filename,
+			Line:        line,
+			Column:      column,
+			Start:       a.start,
+			End:         a.end,
+			Attribute:   a.name,
+			Value:       a.value.Text,
+			Rule:        RawAttribute,
+			Message:     fmt.Sprintf("raw %s attribute", a.name),
+			Suggestion:  "",
+			Replacement: "",
+			Imports:     nil,
+		}
+
+		suggestion, err := Suggest(a.name, a.value)
+		switch {
+		case err == nil:
+			f.Suggestion = suggestion.Expr
+			f.Imports = suggestion.Imports
+			switch {
+			case isDataPrefixed(a.name):
+				// The typed call renders the name without the data- prefix, unless hx is built with htmx.WithDataPrefix, so it isn't rewritten.
+				f.Message += ", which keeps its data- prefix only if hx is built with htmx.WithDataPrefix()"
+			case a.replaceable:
+				f.Replacement = suggestion.Expr
+			}
+		case errors.Is(err, ErrDynamic):
+			f.Message += " with a value computed at runtime, which can't be checked"
+		case errors.Is(err, ErrNoEquivalent):
+			f.Message += ": " + err.Error()
+		case errors.Is(err, ErrUnknownAttribute):
+			f.Rule = UnknownAttribute
+			f.Message = err.Error()
+		default:
+			f.Rule = InvalidValue
+			f.Message = err.Error()
+		}
+
+		findings = append(findings, f)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Start < findings[j].Start
+	})
+	return findings
+}
+
+// position returns the 1-based line and byte column of an offset.
+func position(src []byte, offset int) (line int, column int) {
+	before := string(src[:offset])
+	line = strings.Count(before, "\n") + 1
+	column = offset - strings.LastIndex(before, "\n")
+	return line, column
+}
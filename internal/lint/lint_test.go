@@ -0,0 +1,234 @@
+package lint_test
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"strings"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/internal/lint"
+)
+
+func ExampleTempl() {
+	src := `templ search() {
+	<input type="search" hx-post="/search" hx-trigger="input changed delay:500ms, search" hx-swap="outerHtml"/>
+}`
+	for _, f := range lint.Templ("search.templ", []byte(src)) {
+		fmt.Println(f)
+	}
+	// Output:
+	// search.templ:2:23: raw hx-post attribute (suggest: { hx.Post("/search")... })
+	// search.templ:2:41: raw hx-trigger attribute (suggest: { hx.TriggerExtended(trigger.On("input").Changed().Delay(500 * time.Millisecond), trigger.On("search"))... })
+	// search.templ:2:88: swap: invalid hx-swap value: unknown strategy "outerHtml"
+}
+
+func ExampleGo() {
+	src := `package page
+
+func row() g.Node {
+	return Tr(g.Attr("hx-target", "closest tr"), g.Attr("hx-tigger", "click"))
+}`
+	findings, _ := lint.Go("page.go", []byte(src))
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+	// Output:
+	// page.go:4:12: raw hx-target attribute (suggest: hx.Target(htmx.TargetRelative(htmx.Closest, "tr")))
+	// page.go:4:47: unknown htmx attribute "hx-tigger", did you mean hx-trigger?
+}
+
+func TestTempl(t *testing.T) {
+	src := `package page
+
+templ page(url string) {
+	<div
+		class="list"
+		{ hx.Get("/{id}")... }
+		if url != "" {
+			hx-get={ url }
+		}
+		data-hx-swap='beforeend swap:1s'
+		hx-disable
+	>
+		<p>hx-get="/not-an-attribute"</p>
+		<script>const el = "<div hx-get='/script'>"</script>
+		<button hx-vals='{"page": 2, "q": "x"}' hx-on::after-request="done()">Next</button>
+	</div>
+}`
+	want := []struct {
+		line      int
+		attribute string
+		rule      lint.Rule
+		suggest   string
+	}{
+		{line: 8, attribute: "hx-get", rule: lint.RawAttribute, suggest: "{ hx.Get(url)... }"},
+		{line: 10, attribute: "data-hx-swap", rule: lint.RawAttribute, suggest: ""},
+		{line: 11, attribute: "hx-disable", rule: lint.RawAttribute, suggest: "{ hx.Disable()... }"},
+		{line: 15, attribute: "hx-vals", rule: lint.RawAttribute, suggest: `{ hx.Vals(map[string]any{"page": 2, "q": "x"})... }`},
+		{line: 15, attribute: "hx-on::after-request", rule: lint.RawAttribute, suggest: `{ hx.On("htmx:after-request", "done()")... }`},
+	}
+
+	got := lint.Templ("page.templ", []byte(src))
+	if len(got) != len(want) {
+		t.Fatalf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for i, f := range got {
+		if f.Line != want[i].line || f.Attribute != want[i].attribute || f.Rule != want[i].rule || f.Replacement != want[i].suggest {
+			t.Errorf("finding %d: got %v, want %+v", i, f, want[i])
+		}
+		if !strings.HasPrefix(src[f.Start:f.End], f.Attribute) {
+			t.Errorf("finding %d: range %d-%d doesn't cover %s", i, f.Start, f.End, f.Attribute)
+		}
+	}
+}
+
+func TestGo(t *testing.T) {
+	src := `package page
+
+func page(url string) g.Node {
+	doc.Find("button").Attr("hx-get")
+	return Div(
+		g.Attr("hx-get", url),
+		Data("loading-delay", "200"),
+		g.Attr("class", "list"),
+		templ.Attributes{"hx-swap-oob": "true", "id": "list"},
+	)
+}`
+	got, err := lint.Go("page.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`page.go:6:3: raw hx-get attribute (suggest: hx.Get(url))`,
+		`page.go:7:3: raw data-loading-delay attribute (suggest: loadingstates.DataLoadingDelayBy(hx, 200 * time.Millisecond))`,
+		`page.go:9:20: raw hx-swap-oob attribute (suggest: hx.SwapOOB())`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for i, f := range got {
+		if f.String() != want[i] {
+			t.Errorf("got %q, want %q", f, want[i])
+		}
+	}
+	if got[2].Replacement != "" {
+		t.Errorf("expected templ.Attributes entries to have no in-place replacement, got %q", got[2].Replacement)
+	}
+}
+
+func TestGo_generated(t *testing.T) {
+	src := `// Code generated by templ - DO NOT EDIT.
+
+package page
+
+var attrs = templ.Attributes{"hx-get": "/"}
+`
+	got, err := lint.Go("page_templ.go", []byte(src))
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected generated files to be skipped, got %v, %v", got, err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name    string
+		attr    string
+		value   string
+		want    string
+		imports []string
+	}{
+		{name: "target keyword", attr: "hx-target", value: "this", want: "hx.Target(htmx.TargetThis)", imports: []string{"github.com/will-wow/typed-htmx-go/htmx"}},
+		{name: "target selector", attr: "hx-target", value: "#list", want: `hx.Target("#list")`, imports: []string{}},
+		{name: "include relative", attr: "hx-include", value: "closest form", want: `hx.Include(htmx.IncludeRelative(htmx.Closest, "form"))`},
+		{name: "indicator", attr: "hx-indicator", value: "closest .row", want: `hx.Indicator(htmx.IndicatorRelative(htmx.IndicatorClosest, ".row"))`},
+		{name: "disabled elt", attr: "hx-disabled-elt", value: "this", want: `hx.DisabledElt(htmx.DisabledEltThis)`},
+		{name: "swap", attr: "hx-swap", value: "outerHTML", want: "hx.Swap(swap.OuterHTML)", imports: []string{"github.com/will-wow/typed-htmx-go/htmx/swap"}},
+		{name: "swap modifiers", attr: "hx-swap", value: "scroll:#list:bottom show:window:top settle:20ms transition:true", want: `hx.SwapExtended(swap.New().ScrollElement("#list", swap.Bottom).Settle(20 * time.Millisecond).ShowElement(swap.ShowWindow, swap.Top).Transition())`},
+		{name: "swap oob", attr: "hx-swap-oob", value: "afterbegin:#alerts", want: `hx.SwapOOBSelector(swap.AfterBegin, "#alerts")`},
+		{name: "select oob", attr: "hx-select-oob", value: "#alert:afterbegin,#info", want: `hx.SelectOOBWithStrategy(htmx.SelectOOBStrategy{Selector: "#alert", Strategy: swap.AfterBegin}, htmx.SelectOOBStrategy{Selector: "#info", Strategy: ""})`},
+		{name: "select oob selectors", attr: "hx-select-oob", value: "#alert, #info", want: `hx.SelectOOB("#alert", "#info")`},
+		{name: "trigger", attr: "hx-trigger", value: "load", want: "hx.Trigger(trigger.Load)"},
+		{name: "sse trigger", attr: "hx-trigger", value: "sse:message", want: `sse.Trigger(hx, "message")`},
+		{name: "trigger modifiers", attr: "hx-trigger", value: "click[ctrlKey] from:closest form queue:first once, every 2s [ready]", want: `hx.TriggerExtended(trigger.On("click").When("ctrlKey").From(trigger.FromRelative(trigger.Closest, "form")).Once().Queue(trigger.First), trigger.Every(2 * time.Second).Filter("ready"))`},
+		{name: "intersect", attr: "hx-trigger", value: "intersect root:#list threshold:0.5 once", want: `hx.TriggerExtended(trigger.Intersect().Root("#list").Threshold(0.5).Once())`},
+		{name: "trigger selectors", attr: "hx-trigger", value: "keyup throttle:1s target:(#list input) consume from:next div", want: `hx.TriggerExtended(trigger.On("keyup").Consume().From(trigger.FromRelative(trigger.Next, "div")).Target("#list input").Throttle(time.Second))`},
+		{name: "from document", attr: "hx-trigger", value: "keyup from:body, refresh from:document", want: `hx.TriggerExtended(trigger.On("keyup").From("body"), trigger.On("refresh").From(trigger.FromDocument))`},
+		{name: "boost", attr: "hx-boost", value: "true", want: "hx.Boost(true)"},
+		{name: "push url", attr: "hx-push-url", value: "/page/2", want: `hx.PushURLPath("/page/2")`},
+		{name: "replace url", attr: "hx-replace-url", value: "false", want: `hx.ReplaceURL(false)`},
+		{name: "headers", attr: "hx-headers", value: `{"X-Token": "abc"}`, want: `hx.Headers(map[string]any{"X-Token": "abc"})`},
+		{name: "nested vals", attr: "hx-vals", value: `{"filter": {"q": "x"}}`, want: "hx.Vals(json.RawMessage(`{\"filter\":{\"q\":\"x\"}}`))"},
+		{name: "request", attr: "hx-request", value: `"timeout": 100`, want: `hx.Request(htmx.RequestConfig{Timeout: 100 * time.Millisecond, Credentials: false, NoHeaders: false})`},
+		{name: "sync", attr: "hx-sync", value: "closest form:queue first", want: `hx.SyncStrategy(htmx.SyncRelative(htmx.Closest, "form"), htmx.SyncQueueFirst)`},
+		{name: "sync selector", attr: "hx-sync", value: "this", want: `hx.Sync(htmx.SyncThis)`},
+		{name: "params", attr: "hx-params", value: "not secret, token", want: `hx.ParamsNot("secret", "token")`},
+		{name: "disinherit", attr: "hx-disinherit", value: "hx-target hx-select", want: `hx.Disinherit(htmx.Target, htmx.Select)`},
+		{name: "disinherit all", attr: "hx-disinherit", value: "*", want: `hx.DisinheritAll()`},
+		{name: "inherit", attr: "hx-inherit", value: "hx-target", want: `hx.Inherit(htmx.Target)`},
+		{name: "text content", attr: "hx-swap", value: "textContent", want: `hx.Swap(swap.TextContent)`},
+		{name: "ext", attr: "hx-ext", value: "sse, my-ext", want: `hx.Ext(sse.Extension, "my-ext")`},
+		{name: "ext ignore", attr: "hx-ext", value: "ignore:sse", want: `hx.ExtIgnore("sse")`},
+		{name: "encoding", attr: "hx-encoding", value: "multipart/form-data", want: `hx.Encoding(htmx.EncodingMultipart)`},
+		{name: "on", attr: "hx-on:click", value: "alert(1)", want: `hx.On("click", "alert(1)")`},
+		{name: "response target", attr: "hx-target-5*", value: "#errors", want: `responsetargets.Target(hx, responsetargets.Wildcard(5), "#errors")`},
+		{name: "response target status", attr: "hx-target-404", value: "next .error", want: `responsetargets.Target(hx, responsetargets.Status(404), htmx.TargetRelative(htmx.Next, ".error"))`},
+		{name: "sse swap", attr: "sse-swap", value: "message,update", want: `sse.Swap(hx, "message", "update")`},
+		{name: "classes", attr: "classes", value: "add foo:1s, remove bar", want: `classtools.Classes(hx, classtools.Add("foo", time.Second), classtools.Remove("bar", 100 * time.Millisecond))`},
+		{name: "parallel classes", attr: "data-classes", value: "add foo & toggle bar:2s", want: `classtools.ClassesParallel(hx, []classtools.Run{{classtools.Add("foo", 100 * time.Millisecond)}, {classtools.Toggle("bar", 2 * time.Second)}})`},
+		{name: "preload", attr: "preload", value: "mouseover", want: `preload.PreloadOn(hx, preload.MouseOver)`},
+		{name: "remove me", attr: "remove-me", value: "1s", want: `removeme.RemoveMe(hx, time.Second)`},
+		{name: "data loading", attr: "data-loading", value: "flex", want: `loadingstates.DataLoadingStyle(hx, "flex")`},
+		{name: "ws send", attr: "ws-send", value: "", want: `ws.Send(hx)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lint.Suggest(tt.attr, lint.Value{Text: tt.value, Expr: false, Present: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Expr != tt.want {
+				t.Errorf("got %s, want %s", got.Expr, tt.want)
+			}
+			if _, err := parser.ParseExpr(got.Expr); err != nil {
+				t.Errorf("invalid Go expression %s: %v", got.Expr, err)
+			}
+			if tt.imports != nil && fmt.Sprint(got.Imports) != fmt.Sprint(tt.imports) {
+				t.Errorf("got imports %v, want %v", got.Imports, tt.imports)
+			}
+		})
+	}
+}
+
+func TestSuggest_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		attr  string
+		value lint.Value
+		want  error
+		msg   string
+	}{
+		{name: "unknown", attr: "hx-got", value: lint.Value{Text: "/", Expr: false, Present: true}, want: lint.ErrUnknownAttribute, msg: `unknown htmx attribute "hx-got", did you mean hx-get?`},
+		{name: "no equivalent", attr: "hx-swap", value: lint.Value{Text: "innerHTML transition:false", Expr: false, Present: true}, want: lint.ErrNoEquivalent, msg: "no typed equivalent for transition:false"},
+		{name: "dynamic", attr: "hx-trigger", value: lint.Value{Text: "events", Expr: true, Present: true}, want: lint.ErrDynamic, msg: "value is computed at runtime"},
+		{name: "invalid trigger", attr: "hx-trigger", value: lint.Value{Text: "click delay:soon", Expr: false, Present: true}, want: nil, msg: `trigger: invalid hx-trigger value: delay: invalid timing "soon" in "click delay:soon"`},
+		{name: "invalid bool", attr: "hx-boost", value: lint.Value{Text: "yes", Expr: false, Present: true}, want: nil, msg: `invalid hx-boost value "yes": expected true or false`},
+		{name: "invalid json", attr: "hx-vals", value: lint.Value{Text: `{"a": }`, Expr: false, Present: true}, want: nil, msg: "invalid hx-vals JSON: invalid character '}' looking for beginning of value"},
+		{name: "invalid response target", attr: "hx-target-4000", value: lint.Value{Text: "#e", Expr: false, Present: true}, want: nil, msg: `responsetargets: invalid response code: "hx-target-4000"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := lint.Suggest(tt.attr, tt.value)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+			if err.Error() != tt.msg {
+				t.Errorf("got %q, want %q", err, tt.msg)
+			}
+		})
+	}
+}
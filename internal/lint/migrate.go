@@ -0,0 +1,465 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Rules reported by [Migrate], for code that behaves differently after upgrading from htmx 1.x to 2.x.
+const (
+	V2Version      Rule = "v2-version"       // An HX that renders attributes for htmx 1.x.
+	V2DeleteParams Rule = "v2-delete-params" // A DELETE request, which sends its parameters in the query string in htmx 2.
+	V2SelfRequests Rule = "v2-self-requests" // A request to another origin, which htmx 2 blocks by default.
+	V2HxOn         Rule = "v2-hx-on"         // An hx-on attribute using syntax that htmx 2 doesn't support.
+	V2Removed      Rule = "v2-removed"       // An attribute or config key that htmx 2 removed.
+	V2Extension    Rule = "v2-extension"     // An extension, which htmx 2 publishes as a separate package.
+	V2Config       Rule = "v2-config"        // A config that relies on defaults that changed in htmx 2.
+	V2Script       Rule = "v2-script"        // A script tag that loads htmx 1.x or one of its extensions.
+)
+
+const (
+	htmxPath     = "github.com/will-wow/typed-htmx-go/htmx"
+	hxconfigPath = "github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+	extPath      = "github.com/will-wow/typed-htmx-go/htmx/ext/"
+
+	// v2Script is the htmx 2 release that script tags are upgraded to.
+	v2Script = "htmx.org@2.0.2"
+)
+
+// constructors are the functions that create an HX, and take options.
+var constructors = map[string]bool{"NewHX": true, "NewTempl": true, "NewGomponents": true, "NewStringAttrs": true, "NewHTMLTemplate": true, "FuncMap": true}
+
+// requestMethods are the HX methods that issue a request to a URL.
+var requestMethods = map[string]bool{"Get": true, "Post": true, "Put": true, "Patch": true, "Delete": true}
+
+// requestAttributes are the attributes that issue a request to a URL.
+var requestAttributes = map[string]bool{"hx-get": true, "hx-post": true, "hx-put": true, "hx-patch": true, "hx-delete": true}
+
+// changedDefaults are the config options whose defaults changed in htmx 2, with the setter that keeps the 1.x behavior.
+var changedDefaults = []struct {
+	method string
+	change string
+	keep   string
+}{
+	{method: "SelfRequestsOnly", change: "selfRequestsOnly from false to true", keep: ".SelfRequestsOnly(false)"},
+	{method: "MethodsThatUseUrlParams", change: `methodsThatUseUrlParams from ["get"] to ["get", "delete"]`, keep: ".MethodsThatUseUrlParams([]hxconfig.HTTPMethod{hxconfig.MethodGet})"},
+	{method: "ScrollBehavior", change: "scrollBehavior from smooth to instant", keep: ".ScrollBehavior(hxconfig.ScrollBehaviorSmooth)"},
+}
+
+var (
+	reHTMXScript = regexp.MustCompile(`https?://[^\s"'<>\\` + "`" + `]*htmx\.org@1[^\s"'<>\\` + "`" + `]*`)
+	reExtScript  = regexp.MustCompile(`/dist/ext/([a-z0-9-]+)\.js$`)
+	reV1Release  = regexp.MustCompile(`htmx\.org@1[0-9.]*`)
+)
+
+// Migrate analyzes templ and Go files, keyed by filename, for code that behaves differently after upgrading from htmx 1.x to 2.x.
+// It looks at how the files use [htmx.HX], hxconfig.Builder and the ext packages, and at raw attributes and script tags.
+// Each finding suggests the code change to make, or how to keep the 1.x behavior.
+//
+// Files are analyzed together, so an hx variable declared in a Go file is recognized in the templ files of the same package.
+// An explicit SelfRequestsOnly config setting only covers the requests in its own package, which is the files in the same directory.
+func Migrate(files map[string][]byte) ([]Finding, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	m := &migration{
+		hxNames:          map[string]bool{"hx": true},
+		selfRequestsOnly: map[string]bool{},
+		files:            map[string]*migrationFile{},
+	}
+
+	// The first pass finds the hx variables and each package's config, which affect the findings in other files.
+	for _, name := range names {
+		f, err := m.parse(name, files[name])
+		if err != nil {
+			return nil, err
+		}
+		m.files[name] = f
+	}
+
+	var findings []Finding
+	for _, name := range names {
+		findings = append(findings, m.check(m.files[name])...)
+	}
+	return findings, nil
+}
+
+// migration holds the state shared by every file in an analysis.
+type migration struct {
+	hxNames          map[string]bool // Variables assigned an HX.
+	selfRequestsOnly map[string]bool // The packages, by directory, that set selfRequestsOnly explicitly.
+	files            map[string]*migrationFile
+}
+
+// migrationFile is a parsed source file.
+type migrationFile struct {
+	name     string
+	src      []byte
+	tokens   []tok
+	htmx     string // The name the htmx package is imported as.
+	hxconfig string // The name the hxconfig package is imported as.
+	// Generated files, like templ's output, are only used to find hx variables.
+	generated bool
+	findings  []Finding
+}
+
+// tok is a Go token, with its position in the source.
+type tok struct {
+	tok   token.Token
+	lit   string
+	start int
+	end   int
+}
+
+func (t tok) is(kind token.Token, lit string) bool {
+	return t.tok == kind && (lit == "" || t.lit == lit)
+}
+
+// parse reads the imports and tokens of a file, and records its hx variables and config.
+// Templ files are tokenized as Go, which skips over the HTML while still finding the Go expressions.
+func (m *migration) parse(name string, src []byte) (*migrationFile, error) {
+	f := &migrationFile{
+		name:      name,
+		src:       src,
+		tokens:    nil,
+		htmx:      "",
+		hxconfig:  "",
+		generated: false,
+		findings:  nil,
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("lint: parsing imports of %s: %w", name, err)
+	}
+	f.generated = ast.IsGenerated(file)
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		importAs := importName(spec)
+		switch {
+		case path == htmxPath:
+			f.htmx = importAs
+		case path == hxconfigPath:
+			f.hxconfig = importAs
+		case strings.HasPrefix(path, extPath) && !f.generated:
+			start := fset.Position(spec.Pos()).Offset
+			end := fset.Position(spec.End()).Offset
+			f.findings = append(f.findings, m.extension(f, start, end, extensionName(strings.TrimPrefix(path, extPath))))
+		}
+	}
+
+	var s scanner.Scanner
+	tokenFile := token.NewFileSet().AddFile(name, -1, len(src))
+	s.Init(tokenFile, src, func(token.Position, string) {}, 0)
+	for {
+		pos, kind, lit := s.Scan()
+		if kind == token.EOF {
+			break
+		}
+		// Skip the semicolons inserted at newlines.
+		if kind == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		if lit == "" {
+			lit = kind.String()
+		}
+		start := tokenFile.Offset(pos)
+		f.tokens = append(f.tokens, tok{tok: kind, lit: lit, start: start, end: start + len(lit)})
+	}
+
+	for i, t := range f.tokens {
+		// An HX variable, like `var hx = htmx.NewTempl()` or `hx := htmx.NewGomponents()`.
+		if f.htmx != "" && t.tok == token.IDENT && i+4 < len(f.tokens) &&
+			(f.tokens[i+1].is(token.ASSIGN, "") || f.tokens[i+1].is(token.DEFINE, "")) &&
+			f.tokens[i+2].is(token.IDENT, f.htmx) && f.tokens[i+3].is(token.PERIOD, "") && constructors[f.tokens[i+4].lit] {
+			m.hxNames[t.lit] = true
+		}
+		// The config only covers the pages of its own package.
+		if t.is(token.IDENT, "SelfRequestsOnly") {
+			m.selfRequestsOnly[filepath.Dir(name)] = true
+		}
+	}
+
+	return f, nil
+}
+
+// check runs the migration checks on a parsed file.
+func (m *migration) check(f *migrationFile) []Finding {
+	if f.generated {
+		return nil
+	}
+	findings := f.findings
+
+	for i := 0; i+3 < len(f.tokens); i++ {
+		t := f.tokens
+		if t[i].tok != token.IDENT || !t[i+1].is(token.PERIOD, "") || t[i+2].tok != token.IDENT || !t[i+3].is(token.LPAREN, "") {
+			continue
+		}
+		end := matchParen(t, i+3)
+		receiver, method := t[i].lit, t[i+2].lit
+
+		switch {
+		case receiver == f.htmx && constructors[method]:
+			if finding, ok := m.constructor(f, i, end); ok {
+				findings = append(findings, finding)
+			}
+		case receiver == f.hxconfig && method == "New":
+			findings = append(findings, m.config(f, i, end)...)
+		case m.hxNames[receiver]:
+			findings = append(findings, m.call(f, method, i, end)...)
+		}
+	}
+
+	if filepath.Ext(f.name) == ".templ" {
+		for _, a := range scanTempl(f.src) {
+			findings = append(findings, m.attribute(f, a)...)
+		}
+	}
+
+	for _, loc := range reHTMXScript.FindAllIndex(f.src, -1) {
+		url := string(f.src[loc[0]:loc[1]])
+		message, replacement := "script loads htmx 1.x", reV1Release.ReplaceAllString(url, v2Script)
+		if ext := reExtScript.FindStringSubmatch(url); ext != nil {
+			message = fmt.Sprintf("script loads the %s extension from htmx 1.x", ext[1])
+			replacement = fmt.Sprintf("https://unpkg.com/htmx-ext-%s@2/%s.js", ext[1], ext[1])
+		}
+		findings = append(findings, m.finding(f, loc[0], loc[1], V2Script, "", url, message, replacement, replacement))
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Start < findings[j].Start
+	})
+	return findings
+}
+
+// constructor checks that an HX is created for htmx 2, by adding a WithVersion option if it's missing.
+func (m *migration) constructor(f *migrationFile, start int, end int) (Finding, bool) {
+	t := f.tokens
+	for _, arg := range t[start:end] {
+		if arg.is(token.IDENT, "WithVersion") {
+			return Finding{}, false
+		}
+	}
+
+	option := fmt.Sprintf("%s.WithVersion(%s.V2)", f.htmx, f.htmx)
+	// Add the option after any other arguments, keeping a trailing comma and newline.
+	src := func(from, to int) string { return string(f.src[from:to]) }
+	var replacement string
+	switch {
+	case end == start+4:
+		replacement = src(t[start].start, t[end].start) + option + ")"
+	case t[end-1].is(token.COMMA, ""):
+		replacement = src(t[start].start, t[end-1].end) + " " + option + "," + src(t[end-1].end, t[end].end)
+	default:
+		replacement = src(t[start].start, t[end-1].end) + ", " + option + src(t[end-1].end, t[end].end)
+	}
+
+	return m.finding(f, t[start].start, t[end].end, V2Version, "", "",
+		fmt.Sprintf("%s.%s renders attributes for htmx 1.x", f.htmx, t[start+2].lit), replacement, replacement), true
+}
+
+// config checks an hxconfig.New() chain for removed options, and for changed defaults it relies on.
+func (m *migration) config(f *migrationFile, start int, end int) []Finding {
+	t := f.tokens
+	var findings []Finding
+
+	set := map[string]bool{}
+	for end+3 < len(t) && t[end+1].is(token.PERIOD, "") && t[end+2].tok == token.IDENT && t[end+3].is(token.LPAREN, "") {
+		dot, method := t[end+1], t[end+2].lit
+		end = matchParen(t, end+3)
+		set[method] = true
+		if method == "UseTemplateFragments" {
+			findings = append(findings, m.finding(f, dot.start, t[end].end, V2Removed, "", "",
+				"config useTemplateFragments was removed in htmx 2, which always uses template fragments; remove the option", "", ""))
+		}
+	}
+
+	var changes, keep []string
+	for _, d := range changedDefaults {
+		if !set[d.method] {
+			changes = append(changes, d.change)
+			keep = append(keep, d.keep)
+		}
+	}
+	if len(changes) > 0 {
+		chain := string(f.src[t[start].start:t[end].end])
+		findings = append(findings, m.finding(f, t[start].start, t[end].end, V2Config, "", "",
+			fmt.Sprintf("htmx 2 changes the default %s; set them to keep the 1.x behavior", strings.Join(changes, ", ")),
+			chain+strings.Join(keep, ""), ""))
+	}
+	return findings
+}
+
+// call checks a method call on an HX.
+func (m *migration) call(f *migrationFile, method string, start int, end int) []Finding {
+	t := f.tokens
+	var findings []Finding
+	var url string
+	if start+4 < end && t[start+4].tok == token.STRING {
+		url, _ = strconv.Unquote(t[start+4].lit)
+	}
+
+	if method == "Delete" {
+		findings = append(findings, m.deleteParams(f, t[start].start, t[end].end, url))
+	}
+	if requestMethods[method] && isCrossOrigin(url) && !m.selfRequestsOnly[filepath.Dir(f.name)] {
+		findings = append(findings, m.selfRequests(f, t[start].start, t[end].end, url))
+	}
+	if method == "On" && url != "" {
+		if event, ok := strings.CutPrefix(url, "htmx:"); ok && event != kebab(event) {
+			replacement := strconv.Quote("htmx:" + kebab(event))
+			findings = append(findings, m.finding(f, t[start+4].start, t[start+4].end, V2HxOn, "hx-on", url,
+				fmt.Sprintf("htmx 2 only supports kebab-case event names in hx-on attributes, so %q never fires", url), replacement, replacement))
+		}
+	}
+	return findings
+}
+
+// attribute checks a raw attribute in a templ file.
+func (m *migration) attribute(f *migrationFile, a attribute) []Finding {
+	name, _ := Normalize(a.name)
+	value := a.value.Text
+	var findings []Finding
+
+	if name == "hx-delete" {
+		findings = append(findings, m.deleteParams(f, a.start, a.end, value))
+	}
+	if requestAttributes[name] && !a.value.Expr && isCrossOrigin(value) && !m.selfRequestsOnly[filepath.Dir(f.name)] {
+		findings = append(findings, m.selfRequests(f, a.start, a.end, value))
+	}
+
+	switch {
+	case name == "hx-on":
+		findings = append(findings, m.finding(f, a.start, a.end, V2Removed, a.name, value,
+			`the hx-on="event: script" form was removed in htmx 2`, `{ hx.On("event", "script")... }`, ""))
+	case strings.HasPrefix(name, "hx-on"):
+		// Attribute names are case-insensitive, so use the original source to find camelCase events.
+		prefix := a.name[:strings.Index(strings.ToLower(a.name), "hx-on")+len("hx-on")]
+		rest := a.name[len(prefix):]
+		var event, separator string
+		switch {
+		case strings.HasPrefix(rest, ":htmx:"):
+			event, separator = rest[len(":htmx:"):], "::"
+		case strings.HasPrefix(rest, "::"), strings.HasPrefix(rest, "--"):
+			event, separator = rest[2:], rest[:2]
+			if event == kebab(event) {
+				return findings
+			}
+		default:
+			return findings
+		}
+		replacement := prefix + separator + kebab(event) + string(f.src[a.start+len(a.name):a.end])
+		findings = append(findings, m.finding(f, a.start, a.end, V2HxOn, a.name, value,
+			"htmx 2 uses the hx-on::event shorthand and kebab-case names for htmx events", replacement, replacement))
+	case name == "hx-sse" || name == "hx-ws":
+		ext := strings.TrimPrefix(name, "hx-")
+		findings = append(findings, m.finding(f, a.start, a.end, V2Removed, a.name, value,
+			fmt.Sprintf("%s was removed in htmx 2; use the %s extension", name, ext),
+			fmt.Sprintf("{ hx.Ext(%s.Extension)... } { %s.Connect(hx, url)... }", ext, ext), ""))
+	case name == "hx-ext" && !a.value.Expr:
+		for _, ext := range strings.Split(value, ",") {
+			if ext = strings.TrimSpace(ext); ext != "" && !strings.HasPrefix(ext, "ignore:") {
+				findings = append(findings, m.extension(f, a.start, a.end, ext))
+			}
+		}
+	}
+	return findings
+}
+
+// deleteParams reports a DELETE request, which sends its parameters in the query string in htmx 2.
+func (m *migration) deleteParams(f *migrationFile, start int, end int, url string) Finding {
+	return m.finding(f, start, end, V2DeleteParams, "hx-delete", url,
+		"htmx 2 sends the parameters of DELETE requests in the query string instead of the body; read them with r.URL.Query(), or keep the 1.x behavior with the config",
+		"hxconfig.New().MethodsThatUseUrlParams([]hxconfig.HTTPMethod{hxconfig.MethodGet})", "")
+}
+
+// selfRequests reports a request to another origin, which htmx 2 blocks by default.
+func (m *migration) selfRequests(f *migrationFile, start int, end int, url string) Finding {
+	return m.finding(f, start, end, V2SelfRequests, "", url,
+		fmt.Sprintf("htmx 2 only allows requests to the same origin by default, so the request to %s will be blocked", url),
+		"hxconfig.New().SelfRequestsOnly(false)", "")
+}
+
+// extension reports an extension, which htmx 2 publishes as a separate package.
+func (m *migration) extension(f *migrationFile, start int, end int, ext string) Finding {
+	return m.finding(f, start, end, V2Extension, "hx-ext", ext,
+		fmt.Sprintf("the %s extension is published separately for htmx 2; load it from its own package", ext),
+		fmt.Sprintf(`<script src="https://unpkg.com/htmx-ext-%s@2/%s.js"></script>`, ext, ext), "")
+}
+
+// finding creates a migration finding.
+func (m *migration) finding(f *migrationFile, start int, end int, rule Rule, attr string, value string, message string, suggestion string, replacement string) Finding {
+	line, column := position(f.src, start)
+	return Finding{
+		File:        f.name,
+		Line:        line,
+		Column:      column,
+		Start:       start,
+		End:         end,
+		Attribute:   attr,
+		Value:       value,
+		Rule:        rule,
+		Message:     message,
+		Suggestion:  suggestion,
+		Replacement: replacement,
+		Imports:     nil,
+	}
+}
+
+// matchParen returns the index of the token that closes the parenthesis at i.
+func matchParen(tokens []tok, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i].tok {
+		case token.LPAREN:
+			depth++
+		case token.RPAREN:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// extensionName returns the htmx name of an ext package, like "class-tools" for classtools.
+func extensionName(pkg string) string {
+	for name, p := range extensions {
+		if p == pkg {
+			return name
+		}
+	}
+	return pkg
+}
+
+// isCrossOrigin checks if a URL is absolute, and so may be on another origin.
+func isCrossOrigin(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "//")
+}
+
+// kebab converts a camelCase event name, like afterRequest, to kebab-case.
+func kebab(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('-')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,322 @@
+package lint_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/internal/lint"
+)
+
+func ExampleMigrate() {
+	files := map[string][]byte{
+		"page.go": []byte(`package page
+
+import "github.com/will-wow/typed-htmx-go/htmx"
+
+var hx = htmx.NewTempl()
+`),
+		"page.templ": []byte(`package page
+
+templ row(id string) {
+	<tr>
+		<button { hx.Delete("/contacts/" + id)... } hx-on:htmx:after-request="done()">Delete</button>
+	</tr>
+}`),
+	}
+	findings, _ := lint.Migrate(files)
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+	// Output:
+	// page.go:5:10: htmx.NewTempl renders attributes for htmx 1.x (suggest: htmx.NewTempl(htmx.WithVersion(htmx.V2)))
+	// page.templ:5:13: htmx 2 sends the parameters of DELETE requests in the query string instead of the body; read them with r.URL.Query(), or keep the 1.x behavior with the config (suggest: hxconfig.New().MethodsThatUseUrlParams([]hxconfig.HTTPMethod{hxconfig.MethodGet}))
+	// page.templ:5:47: htmx 2 uses the hx-on::event shorthand and kebab-case names for htmx events (suggest: hx-on::after-request="done()")
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		src         string
+		rule        lint.Rule
+		suggestion  string
+		replacement string
+	}{
+		{
+			name: "constructor with options",
+			file: "page.go",
+			src: `package page
+
+import h "github.com/will-wow/typed-htmx-go/htmx"
+
+var hx = h.NewGomponents(h.OnError(report))`,
+			rule:        lint.V2Version,
+			suggestion:  "h.NewGomponents(h.OnError(report), h.WithVersion(h.V2))",
+			replacement: "h.NewGomponents(h.OnError(report), h.WithVersion(h.V2))",
+		},
+		{
+			name: "constructor with trailing comma",
+			file: "page.go",
+			src: `package page
+
+import "github.com/will-wow/typed-htmx-go/htmx"
+
+var hx = htmx.NewStringAttrs(
+	htmx.OnError(report),
+)`,
+			rule:        lint.V2Version,
+			suggestion:  "htmx.NewStringAttrs(\n\thtmx.OnError(report), htmx.WithVersion(htmx.V2),\n)",
+			replacement: "htmx.NewStringAttrs(\n\thtmx.OnError(report), htmx.WithVersion(htmx.V2),\n)",
+		},
+		{
+			name: "cross-origin request",
+			file: "page.go",
+			src: `package page
+
+func search() g.Node {
+	return Input(hx.Get("https://api.example.com/search"))
+}`,
+			rule:       lint.V2SelfRequests,
+			suggestion: "hxconfig.New().SelfRequestsOnly(false)",
+		},
+		{
+			name: "raw cross-origin request",
+			file: "page.templ",
+			src: `package page
+
+templ search() {
+	<input hx-post="//api.example.com/search"/>
+}`,
+			rule:       lint.V2SelfRequests,
+			suggestion: "hxconfig.New().SelfRequestsOnly(false)",
+		},
+		{
+			name: "raw delete",
+			file: "page.templ",
+			src: `package page
+
+templ row() {
+	<button data-hx-delete="/contacts/1">Delete</button>
+}`,
+			rule:       lint.V2DeleteParams,
+			suggestion: "hxconfig.New().MethodsThatUseUrlParams([]hxconfig.HTTPMethod{hxconfig.MethodGet})",
+		},
+		{
+			name: "camelCase event",
+			file: "page.go",
+			src: `package page
+
+func form() g.Node {
+	return Form(hx.On("htmx:afterRequest", "this.reset()"))
+}`,
+			rule:        lint.V2HxOn,
+			suggestion:  `"htmx:after-request"`,
+			replacement: `"htmx:after-request"`,
+		},
+		{
+			name: "camelCase shorthand",
+			file: "page.templ",
+			src: `package page
+
+templ form() {
+	<form hx-on::afterRequest="this.reset()"></form>
+}`,
+			rule:        lint.V2HxOn,
+			suggestion:  `hx-on::after-request="this.reset()"`,
+			replacement: `hx-on::after-request="this.reset()"`,
+		},
+		{
+			name: "dash syntax",
+			file: "page.templ",
+			src: `package page
+
+templ form() {
+	<form data-hx-on--beforeRequest="start()"></form>
+}`,
+			rule:        lint.V2HxOn,
+			suggestion:  `data-hx-on--before-request="start()"`,
+			replacement: `data-hx-on--before-request="start()"`,
+		},
+		{
+			name: "old hx-on syntax",
+			file: "page.templ",
+			src: `package page
+
+templ form() {
+	<form hx-on="htmx:afterRequest: this.reset()"></form>
+}`,
+			rule:       lint.V2Removed,
+			suggestion: `{ hx.On("event", "script")... }`,
+		},
+		{
+			name: "hx-sse",
+			file: "page.templ",
+			src: `package page
+
+templ feed() {
+	<div hx-sse="connect:/events"></div>
+}`,
+			rule:       lint.V2Removed,
+			suggestion: "{ hx.Ext(sse.Extension)... } { sse.Connect(hx, url)... }",
+		},
+		{
+			name: "raw extension",
+			file: "page.templ",
+			src: `package page
+
+templ form() {
+	<form hx-ext="json-enc"></form>
+}`,
+			rule:       lint.V2Extension,
+			suggestion: `<script src="https://unpkg.com/htmx-ext-json-enc@2/json-enc.js"></script>`,
+		},
+		{
+			name: "extension package",
+			file: "page.go",
+			src: `package page
+
+import (
+	"github.com/will-wow/typed-htmx-go/htmx/ext/preload"
+)`,
+			rule:       lint.V2Extension,
+			suggestion: `<script src="https://unpkg.com/htmx-ext-preload@2/preload.js"></script>`,
+		},
+		{
+			name: "config defaults",
+			file: "page.go",
+			src: `package page
+
+import "github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+
+var config = hxconfig.New().
+	SelfRequestsOnly(true).
+	ScrollBehavior(hxconfig.ScrollBehaviorSmooth)`,
+			rule:       lint.V2Config,
+			suggestion: "hxconfig.New().\n\tSelfRequestsOnly(true).\n\tScrollBehavior(hxconfig.ScrollBehaviorSmooth).MethodsThatUseUrlParams([]hxconfig.HTTPMethod{hxconfig.MethodGet})",
+		},
+		{
+			name: "removed config",
+			file: "page.go",
+			src: `package page
+
+import "github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+
+var config = hxconfig.New().SelfRequestsOnly(false).MethodsThatUseUrlParams(nil).ScrollBehavior(hxconfig.ScrollBehaviorSmooth).UseTemplateFragments(true)`,
+			rule: lint.V2Removed,
+		},
+		{
+			name: "core script",
+			file: "layout.templ",
+			src: `package layout
+
+templ layout() {
+	<script src="https://unpkg.com/htmx.org@1.9.12/dist/htmx.min.js"></script>
+}`,
+			rule:        lint.V2Script,
+			suggestion:  "https://unpkg.com/htmx.org@2.0.2/dist/htmx.min.js",
+			replacement: "https://unpkg.com/htmx.org@2.0.2/dist/htmx.min.js",
+		},
+		{
+			name: "extension script",
+			file: "layout.go",
+			src: `package layout
+
+func scripts() g.Node {
+	return Script(Src("https://unpkg.com/htmx.org@1.9.12/dist/ext/response-targets.js"))
+}`,
+			rule:        lint.V2Script,
+			suggestion:  "https://unpkg.com/htmx-ext-response-targets@2/response-targets.js",
+			replacement: "https://unpkg.com/htmx-ext-response-targets@2/response-targets.js",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := lint.Migrate(map[string][]byte{tt.file: []byte(tt.src)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(findings) != 1 {
+				t.Fatalf("got %d findings, want 1: %v", len(findings), findings)
+			}
+			got := findings[0]
+			if got.Rule != tt.rule {
+				t.Errorf("got rule %s, want %s", got.Rule, tt.rule)
+			}
+			if got.Suggestion != tt.suggestion {
+				t.Errorf("got suggestion %q, want %q", got.Suggestion, tt.suggestion)
+			}
+			if got.Replacement != tt.replacement {
+				t.Errorf("got replacement %q, want %q", got.Replacement, tt.replacement)
+			}
+			if text := tt.src[got.Start:got.End]; got.Replacement != "" && text == got.Replacement {
+				t.Errorf("replacement doesn't change %q", text)
+			}
+		})
+	}
+}
+
+func TestMigrate_compatible(t *testing.T) {
+	files := map[string][]byte{
+		"page.go": []byte(`package page
+
+import (
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+)
+
+var hx = htmx.NewTempl(htmx.WithVersion(htmx.V2))
+
+var config = hxconfig.New().SelfRequestsOnly(false).MethodsThatUseUrlParams(nil).ScrollBehavior(hxconfig.ScrollBehaviorInstant)
+`),
+		"page.templ": []byte(`package page
+
+templ form() {
+	<form { hx.Post("https://api.example.com/contacts")... } hx-on::after-request="this.reset()" hx-on:click="go()">
+		<button { hx.Get("/contacts")... }>Refresh</button>
+	</form>
+}`),
+		"page_templ.go": []byte(`// Code generated by templ - DO NOT EDIT.
+
+package page
+
+import "github.com/will-wow/typed-htmx-go/htmx/ext/sse"
+
+var hx = htmx.NewTempl()
+`),
+	}
+	findings, err := lint.Migrate(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("got findings %v", findings)
+	}
+}
+
+func TestMigrate_selfRequestsOnlyPerPackage(t *testing.T) {
+	files := map[string][]byte{
+		"admin/config.go": []byte(`package admin
+
+import "github.com/will-wow/typed-htmx-go/htmx/hxconfig"
+
+var config = hxconfig.New().SelfRequestsOnly(false).MethodsThatUseUrlParams(nil).ScrollBehavior(hxconfig.ScrollBehaviorInstant)
+`),
+		"admin/page.templ": []byte(`package admin
+
+templ page() {
+	<button hx-get="https://api.example.com/users">Users</button>
+}`),
+		"shop/page.templ": []byte(`package shop
+
+templ page() {
+	<button hx-get="https://api.example.com/products">Products</button>
+}`),
+	}
+	findings, err := lint.Migrate(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Rule != lint.V2SelfRequests || findings[0].File != "shop/page.templ" {
+		t.Errorf("got findings %v, want only the cross-origin request in shop", findings)
+	}
+}
@@ -0,0 +1,189 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Rewrite replaces the raw attributes in a .templ or .go file with their typed equivalents, and adds the imports they need.
+// Templ attributes become spread expressions, like `{ hx.Get("/")... }`, and gomponents attributes become `hx.Get("/")` nodes.
+//
+// Attributes that can't be replaced in place, like invalid values, templ.Attributes entries, or data-hx-* attributes that would lose their prefix, are left untouched and returned as skipped.
+// Rewritten code expects a package-level hx variable, like `var hx = htmx.NewTempl()`.
+func Rewrite(filename string, src []byte) (out []byte, rewritten []Finding, skipped []Finding, err error) {
+	findings, err := File(filename, src)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	imports := map[string]bool{}
+	for _, f := range findings {
+		if f.Replacement == "" {
+			skipped = append(skipped, f)
+			continue
+		}
+		rewritten = append(rewritten, f)
+		for _, path := range f.Imports {
+			imports[path] = true
+		}
+	}
+	if len(rewritten) == 0 {
+		return src, nil, skipped, nil
+	}
+
+	// Replace from the end, so the offsets of earlier findings stay valid.
+	out = bytes.Clone(src)
+	for i := len(rewritten) - 1; i >= 0; i-- {
+		f := rewritten[i]
+		out = append(out[:f.Start], append([]byte(f.Replacement), out[f.End:]...)...)
+	}
+
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	out, err = addImports(filename, out, paths)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if filepath.Ext(filename) == ".go" {
+		out, err = removeUnusedImports(filename, src, out)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		out, err = format.Source(out)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("lint: formatting %s: %w", filename, err)
+		}
+	}
+
+	// Check the output again, so the skipped attributes are reported at their new positions.
+	skipped, err = File(filename, out)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return out, rewritten, skipped, nil
+}
+
+// addImports adds any missing import paths to the import declarations of a Go or templ file.
+// Templ files start with a Go package clause and imports, so only that header is parsed.
+func addImports(filename string, src []byte, paths []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("lint: parsing imports of %s: %w", filename, err)
+	}
+
+	existing := map[string]bool{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		existing[path] = true
+	}
+
+	var missing bytes.Buffer
+	for _, path := range paths {
+		if !existing[path] {
+			fmt.Fprintf(&missing, "\t%q\n", path)
+		}
+	}
+	if missing.Len() == 0 {
+		return src, nil
+	}
+
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	// Add to the last import declaration, turning a single-line import into a block, or start a new block after the package clause.
+	start, end, text := offset(file.Name.End()), offset(file.Name.End()), "\n\nimport (\n"+missing.String()+")"
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			start, end, text = offset(gen.Rparen), offset(gen.Rparen), missing.String()
+		} else {
+			spec := src[offset(gen.Specs[0].Pos()):offset(gen.Specs[0].End())]
+			start, end, text = offset(gen.Pos()), offset(gen.End()), "import (\n\t"+string(spec)+"\n"+missing.String()+")"
+		}
+	}
+
+	out := make([]byte, 0, len(src)+len(text))
+	out = append(out, src[:start]...)
+	out = append(out, text...)
+	out = append(out, src[end:]...)
+	return out, nil
+}
+
+// removeUnusedImports removes the imports that were used before a rewrite, but aren't any more, like a gomponents import only used for g.Attr.
+func removeUnusedImports(filename string, before []byte, after []byte) ([]byte, error) {
+	used, _, _, err := importUses(filename, before)
+	if err != nil {
+		return nil, err
+	}
+	stillUsed, fset, file, err := importUses(filename, after)
+	if err != nil {
+		return nil, err
+	}
+
+	out := after
+	for i := len(file.Imports) - 1; i >= 0; i-- {
+		spec := file.Imports[i]
+		name := importName(spec)
+		if !used[name] || stillUsed[name] {
+			continue
+		}
+
+		// Remove the whole line of the import spec.
+		start := fset.Position(spec.Pos()).Offset
+		end := fset.Position(spec.End()).Offset
+		start = bytes.LastIndexByte(out[:start], '\n') + 1
+		if newline := bytes.IndexByte(out[end:], '\n'); newline >= 0 {
+			end += newline + 1
+		} else {
+			end = len(out)
+		}
+		out = append(out[:start:start], out[end:]...)
+	}
+	return out, nil
+}
+
+// importUses parses a Go file, and returns the names of the imported packages it references.
+func importUses(filename string, src []byte) (map[string]bool, *token.FileSet, *ast.File, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("lint: parsing %s: %w", filename, err)
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return used, fset, file, nil
+}
+
+// importName returns the name an import is referenced by. Unnamed imports are assumed to use the last element of their path.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	path, _ := strconv.Unquote(spec.Path.Value)
+	return filepath.Base(path)
+}
@@ -0,0 +1,184 @@
+package lint_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/will-wow/typed-htmx-go/internal/lint"
+)
+
+func ExampleRewrite() {
+	src := `package page
+
+import "fmt"
+
+templ search() {
+	<input type="search" hx-post="/search" hx-swap="outerHTML settle:1s" hx-trigger="click delay:soon"/>
+}
+`
+	out, rewritten, skipped, _ := lint.Rewrite("search.templ", []byte(src))
+	fmt.Print(string(out))
+	fmt.Println(len(rewritten), "rewritten")
+	fmt.Println(skipped[0])
+	// Output:
+	// package page
+	//
+	// import (
+	// 	"fmt"
+	// 	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	// 	"time"
+	// )
+	//
+	// templ search() {
+	// 	<input type="search" { hx.Post("/search")... } { hx.SwapExtended(swap.New().Strategy(swap.OuterHTML).Settle(time.Second))... } hx-trigger="click delay:soon"/>
+	// }
+	// 2 rewritten
+	// search.templ:10:129: trigger: invalid hx-trigger value: delay: invalid timing "soon" in "click delay:soon"
+}
+
+func TestRewrite_gomponents(t *testing.T) {
+	src := `package page
+
+import (
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+)
+
+func list(url string) g.Node {
+	return Ul(
+		g.Attr("hx-get", url),
+		g.Attr("hx-trigger", "load, every 5s"),
+		g.Attr("hx-swap", "innerHTML transition:false"),
+		Data("loading-target", "#spinner"),
+	)
+}
+`
+	want := `package page
+
+import (
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/loadingstates"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+	"time"
+)
+
+func list(url string) g.Node {
+	return Ul(
+		hx.Get(url),
+		hx.TriggerExtended(trigger.On(trigger.Load), trigger.Every(5*time.Second)),
+		g.Attr("hx-swap", "innerHTML transition:false"),
+		loadingstates.DataLoadingTarget(hx, "#spinner"),
+	)
+}
+`
+	out, rewritten, skipped, err := lint.Rewrite("list.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+	if len(rewritten) != 3 || len(skipped) != 1 || skipped[0].Attribute != "hx-swap" {
+		t.Errorf("got %d rewritten, skipped %v", len(rewritten), skipped)
+	}
+}
+
+func TestRewrite_unusedImport(t *testing.T) {
+	src := `package page
+
+import (
+	"github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+)
+
+var button = Button(gomponents.Attr("hx-post", "/save"))
+`
+	want := `package page
+
+import (
+	. "github.com/maragudk/gomponents/html"
+)
+
+var button = Button(hx.Post("/save"))
+`
+	out, _, _, err := lint.Rewrite("button.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestRewrite_singleImport(t *testing.T) {
+	src := `package page
+
+import g "github.com/maragudk/gomponents"
+
+func list(url string) g.Node {
+	return g.El("ul", g.Attr("hx-get", url), g.Attr("hx-trigger", "every 5s"))
+}
+`
+	want := `package page
+
+import (
+	g "github.com/maragudk/gomponents"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+	"time"
+)
+
+func list(url string) g.Node {
+	return g.El("ul", hx.Get(url), hx.TriggerExtended(trigger.Every(5*time.Second)))
+}
+`
+	out, _, _, err := lint.Rewrite("list.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestRewrite_dataPrefix(t *testing.T) {
+	src := `package page
+
+templ save() {
+	<button data-hx-post="/save" hx-target="#result">Save</button>
+}
+`
+	want := `package page
+
+templ save() {
+	<button data-hx-post="/save" { hx.Target("#result")... }>Save</button>
+}
+`
+	out, rewritten, skipped, err := lint.Rewrite("save.templ", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+	if len(rewritten) != 1 || len(skipped) != 1 {
+		t.Fatalf("got %d rewritten, skipped %v", len(rewritten), skipped)
+	}
+	wantSkipped := `save.templ:4:10: raw data-hx-post attribute, which keeps its data- prefix only if hx is built with htmx.WithDataPrefix() (suggest: hx.Post("/save"))`
+	if got := skipped[0].String(); got != wantSkipped {
+		t.Errorf("got %s, want %s", got, wantSkipped)
+	}
+}
+
+func TestRewrite_unchanged(t *testing.T) {
+	src := `package page
+
+templ page() {
+	<div class="list"></div>
+}
+`
+	out, rewritten, skipped, err := lint.Rewrite("page.templ", []byte(src))
+	if err != nil || string(out) != src || len(rewritten) != 0 || len(skipped) != 0 {
+		t.Errorf("expected no changes, got %q, %v, %v, %v", out, rewritten, skipped, err)
+	}
+}
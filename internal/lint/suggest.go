@@ -0,0 +1,1089 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/will-wow/typed-htmx-go/htmx"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/classtools"
+	"github.com/will-wow/typed-htmx-go/htmx/ext/responsetargets"
+	"github.com/will-wow/typed-htmx-go/htmx/swap"
+	"github.com/will-wow/typed-htmx-go/htmx/trigger"
+)
+
+// ErrUnknownAttribute is returned for an hx-* attribute that htmx doesn't define.
+var ErrUnknownAttribute = errors.New("unknown htmx attribute")
+
+// ErrNoEquivalent is returned for a valid value that the typed API can't express.
+var ErrNoEquivalent = errors.New("no typed equivalent")
+
+// ErrDynamic is returned when a value is computed at runtime, so it can't be parsed.
+var ErrDynamic = errors.New("value is computed at runtime")
+
+// A Value is the value of an attribute found in a source file.
+type Value struct {
+	Text    string // The literal value, or the Go expression that computes it.
+	Expr    bool   // Text is a Go expression, not a literal value.
+	Present bool   // The attribute has a value, instead of being a bare attribute like `hx-disable`.
+}
+
+// A Suggestion is a typed call that replaces a raw attribute.
+type Suggestion struct {
+	Expr    string   // The Go expression, like `hx.Get("/contacts")`.
+	Imports []string // The import paths the expression uses, sorted.
+}
+
+// importPaths maps the package names used in suggestions to their import paths.
+var importPaths = map[string]string{
+	"htmx":            "github.com/will-wow/typed-htmx-go/htmx",
+	"swap":            "github.com/will-wow/typed-htmx-go/htmx/swap",
+	"trigger":         "github.com/will-wow/typed-htmx-go/htmx/trigger",
+	"time":            "time",
+	"json":            "encoding/json",
+	"classtools":      "github.com/will-wow/typed-htmx-go/htmx/ext/classtools",
+	"loadingstates":   "github.com/will-wow/typed-htmx-go/htmx/ext/loadingstates",
+	"preload":         "github.com/will-wow/typed-htmx-go/htmx/ext/preload",
+	"removeme":        "github.com/will-wow/typed-htmx-go/htmx/ext/removeme",
+	"responsetargets": "github.com/will-wow/typed-htmx-go/htmx/ext/responsetargets",
+	"sse":             "github.com/will-wow/typed-htmx-go/htmx/ext/sse",
+	"ws":              "github.com/will-wow/typed-htmx-go/htmx/ext/ws",
+	"ajaxheader":      "github.com/will-wow/typed-htmx-go/htmx/ext/ajaxheader",
+	"alpinemorph":     "github.com/will-wow/typed-htmx-go/htmx/ext/alpinemorph",
+	"debug":           "github.com/will-wow/typed-htmx-go/htmx/ext/debug",
+	"eventheader":     "github.com/will-wow/typed-htmx-go/htmx/ext/eventheader",
+	"restored":        "github.com/will-wow/typed-htmx-go/htmx/ext/restored",
+}
+
+// extensions maps extension names to the packages that define them.
+var extensions = map[string]string{
+	"ajax-header":      "ajaxheader",
+	"alpine-morph":     "alpinemorph",
+	"class-tools":      "classtools",
+	"debug":            "debug",
+	"event-header":     "eventheader",
+	"loading-states":   "loadingstates",
+	"preload":          "preload",
+	"remove-me":        "removeme",
+	"response-targets": "responsetargets",
+	"restored":         "restored",
+	"sse":              "sse",
+	"ws":               "ws",
+}
+
+// attributeNames maps htmx attributes to the names of their [htmx.Attribute] constants.
+var attributeNames = map[htmx.Attribute]string{
+	htmx.Get:         "Get",
+	htmx.Post:        "Post",
+	htmx.PushURL:     "PushURL",
+	htmx.Select:      "Select",
+	htmx.SelectOOB:   "SelectOOB",
+	htmx.Swap:        "Swap",
+	htmx.SwapOOB:     "SwapOOB",
+	htmx.Target:      "Target",
+	htmx.Trigger:     "Trigger",
+	htmx.Vals:        "Vals",
+	htmx.Boost:       "Boost",
+	htmx.Confirm:     "Confirm",
+	htmx.Delete:      "Delete",
+	htmx.Disable:     "Disable",
+	htmx.DisabledElt: "DisabledElt",
+	htmx.Disinherit:  "Disinherit",
+	htmx.Encoding:    "Encoding",
+	htmx.Ext:         "Ext",
+	htmx.Headers:     "Headers",
+	htmx.History:     "History",
+	htmx.HistoryElt:  "HistoryElt",
+	htmx.Include:     "Include",
+	htmx.Indicator:   "Indicator",
+	htmx.Params:      "Params",
+	htmx.Patch:       "Patch",
+	htmx.Preserve:    "Preserve",
+	htmx.Prompt:      "Prompt",
+	htmx.Put:         "Put",
+	htmx.ReplaceURL:  "ReplaceURL",
+	htmx.Request:     "Request",
+	htmx.Sync:        "Sync",
+	htmx.Validate:    "Validate",
+	htmx.Inherit:     "Inherit",
+}
+
+// strategyNames maps swap strategies to the names of their constants.
+var strategyNames = map[swap.Strategy]string{
+	swap.InnerHTML:   "InnerHTML",
+	swap.OuterHTML:   "OuterHTML",
+	swap.BeforeBegin: "BeforeBegin",
+	swap.AfterBegin:  "AfterBegin",
+	swap.BeforeEnd:   "BeforeEnd",
+	swap.AfterEnd:    "AfterEnd",
+	swap.Delete:      "Delete",
+	swap.None:        "None",
+	swap.TextContent: "TextContent",
+}
+
+// A rule checks the value of an attribute, and returns the Go expression for its typed equivalent.
+type rule func(g *gen, name string, v Value) (string, error)
+
+// rules are the rules for each supported attribute, keyed by the attribute name without any data- prefix.
+var rules = map[string]rule{
+	"hx-get":          call("hx.Get"),
+	"hx-post":         call("hx.Post"),
+	"hx-put":          call("hx.Put"),
+	"hx-patch":        call("hx.Patch"),
+	"hx-delete":       call("hx.Delete"),
+	"hx-confirm":      call("hx.Confirm"),
+	"hx-prompt":       call("hx.Prompt"),
+	"hx-select":       typedCall("hx.Select", "htmx.StandardCSSSelector"),
+	"hx-boost":        boolCall("hx.Boost"),
+	"hx-validate":     boolCall("hx.Validate"),
+	"hx-history":      boolCall("hx.History"),
+	"hx-disable":      flag("hx.Disable()"),
+	"hx-preserve":     flag("hx.Preserve()"),
+	"hx-history-elt":  flag("hx.HistoryElt()"),
+	"hx-push-url":     urlOrBool("hx.PushURL", "hx.PushURLPath"),
+	"hx-replace-url":  urlOrBool("hx.ReplaceURL", "hx.ReplaceURLWith"),
+	"hx-target":       selector("hx.Target", "TargetSelector", "TargetRelative", map[string]string{"this": "TargetThis", "next": "TargetNext", "previous": "TargetPrevious"}, relativeModifiers),
+	"hx-include":      selector("hx.Include", "IncludeSelector", "IncludeRelative", map[string]string{"this": "IncludeThis"}, relativeModifiers),
+	"hx-indicator":    selector("hx.Indicator", "IndicatorSelector", "IndicatorRelative", nil, map[string]string{"closest": "IndicatorClosest"}),
+	"hx-disabled-elt": selector("hx.DisabledElt", "DisabledEltSelector", "DisabledEltRelative", map[string]string{"this": "DisabledEltThis"}, map[string]string{"closest": "DisabledEltClosest"}),
+	"hx-swap":         swapRule,
+	"hx-swap-oob":     swapOOBRule,
+	"hx-select-oob":   selectOOBRule,
+	"hx-trigger":      triggerRule,
+	"hx-vals":         jsonRule("hx.Vals"),
+	"hx-headers":      jsonRule("hx.Headers"),
+	"hx-request":      requestRule,
+	"hx-sync":         syncRule,
+	"hx-params":       paramsRule,
+	"hx-disinherit":   inheritRule("hx.Disinherit"),
+	"hx-inherit":      inheritRule("hx.Inherit"),
+	"hx-ext":          extRule,
+	"hx-encoding":     encodingRule,
+
+	"sse-connect":               extCall("sse", "Connect"),
+	"sse-swap":                  listCall("sse", "Swap"),
+	"sse-close":                 extCall("sse", "Close"),
+	"ws-connect":                extCall("ws", "Connect"),
+	"ws-send":                   extFlag("ws", "Send"),
+	"classes":                   classesRule,
+	"preload":                   preloadRule,
+	"preload-images":            extBoolCall("preload", "PreloadImages"),
+	"remove-me":                 extDurationCall("removeme", "RemoveMe"),
+	"data-loading":              dataLoadingRule,
+	"data-loading-class":        extCall("loadingstates", "DataLoadingClass"),
+	"data-loading-class-remove": extCall("loadingstates", "DataLoadingClassRemove"),
+	"data-loading-disable":      extFlag("loadingstates", "DataLoadingDisable"),
+	"data-loading-aria-busy":    extFlag("loadingstates", "DataLoadingAriaBusy"),
+	"data-loading-delay":        dataLoadingDelayRule,
+	"data-loading-target":       extCall("loadingstates", "DataLoadingTarget"),
+	"data-loading-path":         extCall("loadingstates", "DataLoadingPath"),
+	"data-loading-states":       extFlag("loadingstates", "DataLoadingStates"),
+}
+
+// relativeModifiers are the relative modifiers supported by most extended selectors.
+var relativeModifiers = map[string]string{"closest": "Closest", "find": "Find", "next": "Next", "previous": "Previous"}
+
+// Normalize returns the attribute name used to check an attribute, removing the data- prefix from data-hx-* and data-classes attributes.
+// It returns false if the attribute isn't an htmx or extension attribute.
+func Normalize(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if rest, ok := strings.CutPrefix(name, "data-"); ok && (strings.HasPrefix(rest, "hx-") || rest == "classes") {
+		name = rest
+	}
+
+	switch {
+	case strings.HasPrefix(name, "hx-"), strings.HasPrefix(name, "sse-"), strings.HasPrefix(name, "ws-"), strings.HasPrefix(name, "data-loading"):
+		return name, true
+	default:
+		_, ok := rules[name]
+		return name, ok
+	}
+}
+
+// isDataPrefixed checks if an attribute is the data- prefixed form of an htmx attribute, like data-hx-post.
+func isDataPrefixed(name string) bool {
+	normalized, ok := Normalize(name)
+	return ok && len(normalized) < len(name)
+}
+
+// Suggest checks the value of a raw htmx or extension attribute, and returns the typed call that replaces it.
+// Suggestions use a variable named hx for the [htmx.HX] builder.
+func Suggest(name string, v Value) (Suggestion, error) {
+	name, ok := Normalize(name)
+	if !ok {
+		return Suggestion{}, fmt.Errorf("%w %q", ErrUnknownAttribute, name)
+	}
+
+	r, ok := rules[name]
+	switch {
+	case ok:
+	case strings.HasPrefix(name, "hx-on"):
+		r = onRule
+	case strings.HasPrefix(name, "hx-target-"):
+		r = responseTargetRule
+	default:
+		if closest := closestName(name); closest != "" {
+			return Suggestion{}, fmt.Errorf("%w %q, did you mean %s?", ErrUnknownAttribute, name, closest)
+		}
+		return Suggestion{}, fmt.Errorf("%w %q", ErrUnknownAttribute, name)
+	}
+
+	g := &gen{imports: map[string]bool{}}
+	expr, err := r(g, name, v)
+	if err != nil {
+		return Suggestion{}, err
+	}
+
+	imports := make([]string, 0, len(g.imports))
+	for pkg := range g.imports {
+		imports = append(imports, importPaths[pkg])
+	}
+	sort.Strings(imports)
+
+	return Suggestion{Expr: expr, Imports: imports}, nil
+}
+
+// gen builds Go expressions, and tracks the packages they use.
+type gen struct {
+	imports map[string]bool
+}
+
+// pkg returns a qualified name in a package, and records the import.
+func (g *gen) pkg(pkg string, name string) string {
+	g.imports[pkg] = true
+	return pkg + "." + name
+}
+
+// str returns a Go string expression for a value.
+func (g *gen) str(v Value) string {
+	if v.Expr {
+		return v.Text
+	}
+	return strconv.Quote(v.Text)
+}
+
+// typed returns a Go expression for a value of a named string type. Literals are untyped constants, but expressions need a conversion.
+func (g *gen) typed(v Value, typ string) string {
+	if v.Expr {
+		return fmt.Sprintf("%s(%s)", g.pkg("htmx", typ), v.Text)
+	}
+	return strconv.Quote(v.Text)
+}
+
+// duration returns a Go expression for a duration, like `500 * time.Millisecond`.
+func (g *gen) duration(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{unit: time.Hour, name: "Hour"},
+		{unit: time.Minute, name: "Minute"},
+		{unit: time.Second, name: "Second"},
+		{unit: time.Millisecond, name: "Millisecond"},
+		{unit: time.Microsecond, name: "Microsecond"},
+		{unit: time.Nanosecond, name: "Nanosecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			if d == u.unit {
+				return g.pkg("time", u.name)
+			}
+			return fmt.Sprintf("%d * %s", d/u.unit, g.pkg("time", u.name))
+		}
+	}
+	return strconv.FormatInt(int64(d), 10)
+}
+
+// strategy returns the Go constant for a swap strategy.
+func (g *gen) strategy(s swap.Strategy) string {
+	return g.pkg("swap", strategyNames[s])
+}
+
+// literal returns the value, or ErrDynamic if it's an expression.
+func literal(v Value) (string, error) {
+	if v.Expr {
+		return "", ErrDynamic
+	}
+	return v.Text, nil
+}
+
+// call is a rule for a method that takes a string.
+func call(method string) rule {
+	return func(g *gen, _ string, v Value) (string, error) {
+		return fmt.Sprintf("%s(%s)", method, g.str(v)), nil
+	}
+}
+
+// typedCall is a rule for a method that takes a named string type.
+func typedCall(method string, typ string) rule {
+	return func(g *gen, _ string, v Value) (string, error) {
+		return fmt.Sprintf("%s(%s)", method, g.typed(v, strings.TrimPrefix(typ, "htmx."))), nil
+	}
+}
+
+// boolCall is a rule for a method that takes a boolean.
+func boolCall(method string) rule {
+	return func(g *gen, name string, v Value) (string, error) {
+		on, err := parseBool(name, v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s(%t)", method, on), nil
+	}
+}
+
+// parseBool parses a "true" or "false" attribute value.
+func parseBool(name string, v Value) (bool, error) {
+	text, err := literal(v)
+	if err != nil {
+		return false, err
+	}
+	switch text {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid %s value %q: expected true or false", name, text)
+	}
+}
+
+// flag is a rule for an attribute whose value is ignored.
+func flag(expr string) rule {
+	return func(*gen, string, Value) (string, error) {
+		return expr, nil
+	}
+}
+
+// urlOrBool is a rule for an attribute that takes either a boolean or a URL.
+func urlOrBool(boolMethod string, urlMethod string) rule {
+	return func(g *gen, _ string, v Value) (string, error) {
+		if !v.Expr && (v.Text == "true" || v.Text == "false") {
+			return fmt.Sprintf("%s(%s)", boolMethod, v.Text), nil
+		}
+		return fmt.Sprintf("%s(%s)", urlMethod, g.str(v)), nil
+	}
+}
+
+// selector is a rule for an attribute that takes an extended selector, with non-standard keywords and relative modifiers.
+func selector(method string, typ string, relative string, keywords map[string]string, modifiers map[string]string) rule {
+	return func(g *gen, _ string, v Value) (string, error) {
+		if v.Expr {
+			return fmt.Sprintf("%s(%s)", method, g.typed(v, typ)), nil
+		}
+		text := strings.TrimSpace(v.Text)
+		if keyword, ok := keywords[text]; ok {
+			return fmt.Sprintf("%s(%s)", method, g.pkg("htmx", keyword)), nil
+		}
+		if modifier, rest, found := strings.Cut(text, " "); found {
+			if constant, ok := modifiers[modifier]; ok {
+				return fmt.Sprintf("%s(%s(%s, %q))", method, g.pkg("htmx", relative), g.pkg("htmx", constant), strings.TrimSpace(rest)), nil
+			}
+		}
+		return fmt.Sprintf("%s(%q)", method, text), nil
+	}
+}
+
+// swapRule checks hx-swap with [swap.Parse].
+func swapRule(g *gen, _ string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	b, err := swap.Parse(text)
+	if err != nil {
+		return "", err
+	}
+	return g.swapBuilder(b)
+}
+
+// swapBuilder returns the Go expression for a parsed swap builder, from its canonical form.
+func (g *gen) swapBuilder(b *swap.Builder) (string, error) {
+	fields := strings.Fields(b.String())
+	strategy := swap.Strategy(fields[0])
+	if len(fields) == 1 {
+		return fmt.Sprintf("hx.Swap(%s)", g.strategy(strategy)), nil
+	}
+
+	expr := g.pkg("swap", "New()")
+	if strategy != swap.InnerHTML {
+		expr += fmt.Sprintf(".Strategy(%s)", g.strategy(strategy))
+	}
+
+	for _, field := range fields[1:] {
+		modifier, value, _ := strings.Cut(field, ":")
+		switch swap.Modifier(modifier) {
+		case swap.Transition, swap.IgnoreTitle:
+			if value != "true" {
+				return "", fmt.Errorf("%w for %s:%s", ErrNoEquivalent, modifier, value)
+			}
+			expr += map[swap.Modifier]string{swap.Transition: ".Transition()", swap.IgnoreTitle: ".IgnoreTitle()"}[swap.Modifier(modifier)]
+		case swap.FocusScroll:
+			expr += fmt.Sprintf(".FocusScroll(%s)", value)
+		case swap.Swap, swap.Settle:
+			d, _ := time.ParseDuration(value)
+			expr += fmt.Sprintf(".%s(%s)", map[swap.Modifier]string{swap.Swap: "Swap", swap.Settle: "Settle"}[swap.Modifier(modifier)], g.duration(d))
+		case swap.Scroll, swap.Show:
+			method := "Scroll"
+			if swap.Modifier(modifier) == swap.Show {
+				method = "Show"
+			}
+			if value == "none" {
+				expr += ".ShowNone()"
+				continue
+			}
+			target, direction, found := cutLast(value, ":")
+			dir := g.pkg("swap", map[string]string{"top": "Top", "bottom": "Bottom"}[direction])
+			switch {
+			case !found:
+				expr += fmt.Sprintf(".%s(%s)", method, g.pkg("swap", map[string]string{"top": "Top", "bottom": "Bottom"}[value]))
+			case method == "Show" && target == string(swap.ShowWindow):
+				expr += fmt.Sprintf(".ShowElement(%s, %s)", g.pkg("swap", "ShowWindow"), dir)
+			default:
+				expr += fmt.Sprintf(".%sElement(%q, %s)", method, target, dir)
+			}
+		}
+	}
+
+	return fmt.Sprintf("hx.SwapExtended(%s)", expr), nil
+}
+
+// swapOOBRule checks hx-swap-oob, which is true, a strategy, or a strategy and a selector.
+func swapOOBRule(g *gen, _ string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	if text == "true" || text == "" {
+		return "hx.SwapOOB()", nil
+	}
+
+	value, selector, found := strings.Cut(text, ":")
+	strategy, err := swap.ParseStrategy(value)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return fmt.Sprintf("hx.SwapOOBSelector(%s, %q)", g.strategy(strategy), selector), nil
+	}
+	return fmt.Sprintf("hx.SwapOOBWithStrategy(%s)", g.strategy(strategy)), nil
+}
+
+// selectOOBRule checks hx-select-oob with [htmx.ParseSelectOOB].
+func selectOOBRule(g *gen, _ string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	selectors, err := htmx.ParseSelectOOB(text)
+	if err != nil {
+		return "", err
+	}
+
+	withStrategy := false
+	for _, s := range selectors {
+		withStrategy = withStrategy || s.Strategy != ""
+	}
+
+	args := make([]string, len(selectors))
+	for i, s := range selectors {
+		if !withStrategy {
+			args[i] = strconv.Quote(string(s.Selector))
+			continue
+		}
+		strategy := `""`
+		if s.Strategy != "" {
+			strategy = g.strategy(s.Strategy)
+		}
+		args[i] = fmt.Sprintf("%s{Selector: %q, Strategy: %s}", g.pkg("htmx", "SelectOOBStrategy"), s.Selector, strategy)
+	}
+
+	if withStrategy {
+		return fmt.Sprintf("hx.SelectOOBWithStrategy(%s)", strings.Join(args, ", ")), nil
+	}
+	return fmt.Sprintf("hx.SelectOOB(%s)", strings.Join(args, ", ")), nil
+}
+
+// triggerRule checks hx-trigger with [trigger.Parse].
+func triggerRule(g *gen, _ string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	triggers, err := trigger.Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	if len(triggers) == 1 {
+		if e, ok := triggers[0].(*trigger.Event); ok && !strings.ContainsAny(e.String(), "[ ") {
+			if event, ok := strings.CutPrefix(e.String(), "sse:"); ok {
+				return fmt.Sprintf("%s(hx, %q)", g.pkg("sse", "Trigger"), event), nil
+			}
+			return fmt.Sprintf("hx.Trigger(%s)", g.event(e.String())), nil
+		}
+	}
+
+	args := make([]string, len(triggers))
+	for i, t := range triggers {
+		args[i] = g.trigger(t)
+	}
+	return fmt.Sprintf("hx.TriggerExtended(%s)", strings.Join(args, ", ")), nil
+}
+
+// event returns the Go expression for a trigger event name.
+func (g *gen) event(name string) string {
+	switch trigger.TriggerEvent(name) {
+	case trigger.Load:
+		return g.pkg("trigger", "Load")
+	case trigger.Revealed:
+		return g.pkg("trigger", "Revealed")
+	default:
+		return strconv.Quote(name)
+	}
+}
+
+// trigger returns the Go expression for a parsed trigger, from its canonical form.
+func (g *gen) trigger(t trigger.Trigger) string {
+	value := t.String()
+
+	if _, ok := t.(*trigger.Poll); ok {
+		rest := strings.TrimPrefix(value, "every ")
+		timing, filter, _ := strings.Cut(rest, " ")
+		d, _ := time.ParseDuration(timing)
+		expr := fmt.Sprintf("%s(%s)", g.pkg("trigger", "Every"), g.duration(d))
+		if filter != "" {
+			expr += fmt.Sprintf(".Filter(%q)", strings.TrimSuffix(strings.TrimPrefix(filter, "["), "]"))
+		}
+		return expr
+	}
+
+	name, filter, tokens := splitTrigger(value)
+
+	var intersect, modifiers string
+	if _, ok := t.(*trigger.IntersectEvent); ok {
+		intersect = g.pkg("trigger", "Intersect()")
+	} else {
+		intersect = fmt.Sprintf("%s(%s)", g.pkg("trigger", "On"), g.event(name))
+	}
+	if filter != "" {
+		modifiers += fmt.Sprintf(".When(%q)", filter)
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		modifier, arg, _ := strings.Cut(tokens[i], ":")
+		switch trigger.Modifier(modifier) {
+		case trigger.Once:
+			modifiers += ".Once()"
+		case trigger.Changed:
+			modifiers += ".Changed()"
+		case trigger.Consume:
+			modifiers += ".Consume()"
+		case trigger.Delay, trigger.Throttle:
+			d, _ := time.ParseDuration(arg)
+			modifiers += fmt.Sprintf(".%s(%s)", map[trigger.Modifier]string{trigger.Delay: "Delay", trigger.Throttle: "Throttle"}[trigger.Modifier(modifier)], g.duration(d))
+		case trigger.From:
+			if constant, ok := relativeModifiers[arg]; ok && i+1 < len(tokens) && strings.HasPrefix(tokens[i+1], "(") {
+				i++
+				modifiers += fmt.Sprintf(".From(%s(%s, %q))", g.pkg("trigger", "FromRelative"), g.pkg("trigger", constant), unwrap(tokens[i]))
+				continue
+			}
+			selector := unwrap(arg)
+			if constant, ok := map[string]string{"document": "FromDocument", "window": "FromWindow", "next": "FromNext", "previous": "FromPrevious"}[selector]; ok {
+				modifiers += fmt.Sprintf(".From(%s)", g.pkg("trigger", constant))
+			} else {
+				modifiers += fmt.Sprintf(".From(%q)", selector)
+			}
+		case trigger.Target:
+			modifiers += fmt.Sprintf(".Target(%q)", unwrap(arg))
+		case trigger.Queue:
+			modifiers += fmt.Sprintf(".Queue(%s)", g.pkg("trigger", map[string]string{"first": "First", "last": "Last", "all": "All", "none": "None"}[arg]))
+		case trigger.Root:
+			intersect += fmt.Sprintf(".Root(%q)", unwrap(arg))
+		case trigger.Threshold:
+			intersect += fmt.Sprintf(".Threshold(%s)", arg)
+		}
+	}
+
+	return intersect + modifiers
+}
+
+// splitTrigger splits the canonical form of an event trigger into its name, filter and modifier tokens.
+func splitTrigger(value string) (name string, filter string, tokens []string) {
+	end := strings.IndexAny(value, "[ ")
+	if end == -1 {
+		return value, "", nil
+	}
+	name, rest := value[:end], value[end:]
+
+	if strings.HasPrefix(rest, "[") {
+		depth := 0
+		for i := 0; i < len(rest); i++ {
+			if rest[i] == '[' {
+				depth++
+			} else if rest[i] == ']' {
+				depth--
+				if depth == 0 {
+					filter, rest = rest[1:i], rest[i+1:]
+					break
+				}
+			}
+		}
+	}
+
+	depth := 0
+	start := 0
+	for i := 0; i <= len(rest); i++ {
+		if i < len(rest) {
+			switch rest[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth > 0 || rest[i] != ' ' {
+				continue
+			}
+		}
+		if token := rest[start:i]; token != "" {
+			tokens = append(tokens, token)
+		}
+		start = i + 1
+	}
+	return name, filter, tokens
+}
+
+// unwrap removes the parentheses used to disambiguate a selector.
+func unwrap(selector string) string {
+	if strings.HasPrefix(selector, "(") && strings.HasSuffix(selector, ")") {
+		return selector[1 : len(selector)-1]
+	}
+	return selector
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s string, sep string) (before string, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return "", s, false
+}
+
+// jsonRule checks a JSON attribute, like hx-vals, and suggests a map.
+func jsonRule(method string) rule {
+	return func(g *gen, name string, v Value) (string, error) {
+		text, err := literal(v)
+		if err != nil {
+			return "", err
+		}
+		text = strings.TrimSpace(text)
+		if strings.HasPrefix(text, "js:") || strings.HasPrefix(text, "javascript:") {
+			return "", fmt.Errorf("%w for javascript %s; use %sJS", ErrNoEquivalent, name, method)
+		}
+		if !strings.HasPrefix(text, "{") {
+			text = "{" + text + "}"
+		}
+
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.UseNumber()
+		var values map[string]any
+		if err := decoder.Decode(&values); err != nil {
+			return "", fmt.Errorf("invalid %s JSON: %w", name, err)
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		entries := make([]string, len(keys))
+		for i, key := range keys {
+			var value string
+			switch v := values[key].(type) {
+			case string:
+				value = strconv.Quote(v)
+			case json.Number:
+				value = v.String()
+			case bool:
+				value = strconv.FormatBool(v)
+			case nil:
+				value = "nil"
+			default:
+				var compact bytes.Buffer
+				_ = json.Compact(&compact, []byte(text))
+				return fmt.Sprintf("%s(%s(%s))", method, g.pkg("json", "RawMessage"), "`"+compact.String()+"`"), nil
+			}
+			entries[i] = fmt.Sprintf("%q: %s", key, value)
+		}
+		return fmt.Sprintf("%s(map[string]any{%s})", method, strings.Join(entries, ", ")), nil
+	}
+}
+
+// requestRule checks hx-request with [htmx.ParseRequestConfig].
+func requestRule(g *gen, _ string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	config, err := htmx.ParseRequestConfig(text)
+	if err != nil {
+		if strings.Contains(err.Error(), "javascript") {
+			return "", fmt.Errorf("%w for javascript hx-request; use hx.RequestJS", ErrNoEquivalent)
+		}
+		return "", err
+	}
+	return fmt.Sprintf("hx.Request(%s{Timeout: %s, Credentials: %t, NoHeaders: %t})",
+		g.pkg("htmx", "RequestConfig"), g.duration(config.Timeout), config.Credentials, config.NoHeaders), nil
+}
+
+// syncStrategies maps hx-sync strategies to their constants.
+var syncStrategies = map[string]string{
+	"drop":        "SyncDrop",
+	"abort":       "SyncAbort",
+	"replace":     "SyncReplace",
+	"queue":       "SyncQueue",
+	"queue first": "SyncQueueFirst",
+	"queue last":  "SyncQueueLast",
+	"queue all":   "SyncQueueAll",
+}
+
+// syncRule checks hx-sync, which is a selector and an optional strategy.
+func syncRule(g *gen, name string, v Value) (string, error) {
+	if v.Expr {
+		return fmt.Sprintf("hx.Sync(%s)", g.typed(v, "SyncSelector")), nil
+	}
+
+	selectorValue, strategy, found := cutLast(v.Text, ":")
+	if !found {
+		selectorValue = strategy
+	}
+	sel, _ := selector("", "SyncSelector", "SyncRelative", map[string]string{"this": "SyncThis"}, relativeModifiers)(g, name, Value{Text: selectorValue, Expr: false, Present: true})
+	sel = strings.TrimSuffix(strings.TrimPrefix(sel, "("), ")")
+
+	if !found {
+		return fmt.Sprintf("hx.Sync(%s)", sel), nil
+	}
+	constant, ok := syncStrategies[strings.TrimSpace(strategy)]
+	if !ok {
+		return "", fmt.Errorf("invalid %s strategy %q", name, strategy)
+	}
+	return fmt.Sprintf("hx.SyncStrategy(%s, %s)", sel, g.pkg("htmx", constant)), nil
+}
+
+// paramsRule checks hx-params, which is *, none, a list of names, or "not" and a list of names.
+func paramsRule(_ *gen, _ string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	switch text = strings.TrimSpace(text); text {
+	case "*":
+		return "hx.ParamsAll()", nil
+	case "none":
+		return "hx.ParamsNone()", nil
+	}
+
+	method := "hx.Params"
+	if rest, ok := strings.CutPrefix(text, "not "); ok {
+		method, text = "hx.ParamsNot", rest
+	}
+	return fmt.Sprintf("%s(%s)", method, quoteList(text, ",")), nil
+}
+
+// inheritRule checks hx-inherit and hx-disinherit, which are * or a list of attributes.
+func inheritRule(method string) rule {
+	return func(g *gen, name string, v Value) (string, error) {
+		text, err := literal(v)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(text) == "*" {
+			return method + "All()", nil
+		}
+
+		var args []string
+		for _, attr := range strings.Fields(text) {
+			constant, ok := attributeNames[htmx.Attribute(attr)]
+			if !ok {
+				return "", fmt.Errorf("invalid %s value: %w %q", name, ErrUnknownAttribute, attr)
+			}
+			args = append(args, g.pkg("htmx", constant))
+		}
+		return fmt.Sprintf("%s(%s)", method, strings.Join(args, ", ")), nil
+	}
+}
+
+// extRule checks hx-ext, using the Extension constants of the supported extensions.
+func extRule(g *gen, _ string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	if rest, ok := strings.CutPrefix(strings.TrimSpace(text), "ignore:"); ok {
+		return fmt.Sprintf("hx.ExtIgnore(%q)", rest), nil
+	}
+
+	var args []string
+	for _, ext := range strings.Split(text, ",") {
+		ext = strings.TrimSpace(ext)
+		if pkg, ok := extensions[ext]; ok {
+			args = append(args, g.pkg(pkg, "Extension"))
+		} else {
+			args = append(args, strconv.Quote(ext))
+		}
+	}
+	return fmt.Sprintf("hx.Ext(%s)", strings.Join(args, ", ")), nil
+}
+
+// encodingRule checks hx-encoding, which only supports multipart/form-data.
+func encodingRule(g *gen, name string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	if text != string(htmx.EncodingMultipart) {
+		return "", fmt.Errorf("invalid %s value %q: expected %s", name, text, htmx.EncodingMultipart)
+	}
+	return fmt.Sprintf("hx.Encoding(%s)", g.pkg("htmx", "EncodingMultipart")), nil
+}
+
+// onRule checks hx-on:event attributes, including the hx-on::event shorthand for htmx events.
+func onRule(g *gen, name string, v Value) (string, error) {
+	event, ok := strings.CutPrefix(name, "hx-on:")
+	if !ok {
+		if event, ok = strings.CutPrefix(name, "hx-on-"); !ok {
+			return "", fmt.Errorf("%w for %s; use one hx-on:event attribute per event", ErrNoEquivalent, name)
+		}
+		if rest, ok := strings.CutPrefix(event, "-"); ok {
+			event = ":" + rest
+		}
+	}
+	if rest, ok := strings.CutPrefix(event, ":"); ok {
+		event = "htmx:" + rest
+	}
+	if event == "" {
+		return "", fmt.Errorf("missing event name in %s", name)
+	}
+	return fmt.Sprintf("hx.On(%q, %s)", event, g.str(v)), nil
+}
+
+// responseTargetRule checks the hx-target-code attributes of the response-targets extension.
+func responseTargetRule(g *gen, name string, v Value) (string, error) {
+	code, err := responsetargets.ParseCode(name)
+	if err != nil {
+		return "", err
+	}
+
+	var codeExpr string
+	value := code.String()
+	switch {
+	case value == "error":
+		codeExpr = g.pkg("responsetargets", "Error")
+	case strings.HasSuffix(value, "*"), strings.HasSuffix(value, "x"):
+		fn := "Wildcard"
+		if strings.HasSuffix(value, "x") {
+			fn = "WildcardX"
+		}
+		digits := make([]string, len(value)-1)
+		for i, d := range value[:len(value)-1] {
+			digits[i] = string(d)
+		}
+		codeExpr = fmt.Sprintf("%s(%s)", g.pkg("responsetargets", fn), strings.Join(digits, ", "))
+	default:
+		codeExpr = fmt.Sprintf("%s(%s)", g.pkg("responsetargets", "Status"), value)
+	}
+
+	target, _ := rules["hx-target"](g, name, v)
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "hx.Target("), ")")
+	return fmt.Sprintf("%s(hx, %s, %s)", g.pkg("responsetargets", "Target"), codeExpr, target), nil
+}
+
+// extCall is a rule for an extension function that takes a string.
+func extCall(pkg string, fn string) rule {
+	return func(g *gen, _ string, v Value) (string, error) {
+		return fmt.Sprintf("%s(hx, %s)", g.pkg(pkg, fn), g.str(v)), nil
+	}
+}
+
+// listCall is a rule for an extension function that takes a comma-separated list of strings.
+func listCall(pkg string, fn string) rule {
+	return func(g *gen, _ string, v Value) (string, error) {
+		text, err := literal(v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s(hx, %s)", g.pkg(pkg, fn), quoteList(text, ",")), nil
+	}
+}
+
+// extFlag is a rule for an extension function that takes no value.
+func extFlag(pkg string, fn string) rule {
+	return func(g *gen, _ string, _ Value) (string, error) {
+		return fmt.Sprintf("%s(hx)", g.pkg(pkg, fn)), nil
+	}
+}
+
+// extBoolCall is a rule for an extension function that takes a boolean.
+func extBoolCall(pkg string, fn string) rule {
+	return func(g *gen, name string, v Value) (string, error) {
+		on, err := parseBool(name, v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s(hx, %t)", g.pkg(pkg, fn), on), nil
+	}
+}
+
+// extDurationCall is a rule for an extension function that takes a duration.
+func extDurationCall(pkg string, fn string) rule {
+	return func(g *gen, name string, v Value) (string, error) {
+		text, err := literal(v)
+		if err != nil {
+			return "", err
+		}
+		d, err := parseInterval(text)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s value: %w", name, err)
+		}
+		return fmt.Sprintf("%s(hx, %s)", g.pkg(pkg, fn), g.duration(d)), nil
+	}
+}
+
+// classesRule checks the class-tools classes attribute with [classtools.Parse].
+func classesRule(g *gen, _ string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	runs, err := classtools.Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	runExprs := make([]string, len(runs))
+	for i, run := range runs {
+		ops := strings.Split(run.String(), ", ")
+		for j, op := range ops {
+			operation, rest, _ := strings.Cut(op, " ")
+			class, delay, _ := cutLast(rest, ":")
+			d, _ := time.ParseDuration(delay)
+			fn := strings.ToUpper(operation[:1]) + operation[1:]
+			ops[j] = fmt.Sprintf("%s(%q, %s)", g.pkg("classtools", fn), class, g.duration(d))
+		}
+		runExprs[i] = strings.Join(ops, ", ")
+	}
+
+	if len(runs) == 1 {
+		return fmt.Sprintf("%s(hx, %s)", g.pkg("classtools", "Classes"), runExprs[0]), nil
+	}
+	for i, run := range runExprs {
+		runExprs[i] = "{" + run + "}"
+	}
+	return fmt.Sprintf("%s(hx, []%s{%s})", g.pkg("classtools", "ClassesParallel"), g.pkg("classtools", "Run"), strings.Join(runExprs, ", ")), nil
+}
+
+// preloadRule checks the preload attribute, which is empty or the event that triggers the preload.
+func preloadRule(g *gen, name string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	switch text {
+	case "", "true":
+		return fmt.Sprintf("%s(hx)", g.pkg("preload", "Preload")), nil
+	case "mousedown", "mouseover", "preload:init":
+		constant := map[string]string{"mousedown": "MouseDown", "mouseover": "MouseOver", "preload:init": "Init"}[text]
+		return fmt.Sprintf("%s(hx, %s)", g.pkg("preload", "PreloadOn"), g.pkg("preload", constant)), nil
+	default:
+		return "", fmt.Errorf("invalid %s value %q: expected mousedown, mouseover or preload:init", name, text)
+	}
+}
+
+// dataLoadingRule checks data-loading, which is empty or a display style.
+func dataLoadingRule(g *gen, _ string, v Value) (string, error) {
+	if !v.Present || (!v.Expr && (v.Text == "" || v.Text == "true")) {
+		return fmt.Sprintf("%s(hx)", g.pkg("loadingstates", "DataLoading")), nil
+	}
+	return fmt.Sprintf("%s(hx, %s)", g.pkg("loadingstates", "DataLoadingStyle"), g.str(v)), nil
+}
+
+// dataLoadingDelayRule checks data-loading-delay, which is empty or a delay in milliseconds.
+func dataLoadingDelayRule(g *gen, name string, v Value) (string, error) {
+	text, err := literal(v)
+	if err != nil {
+		return "", err
+	}
+	if text == "" || text == "true" {
+		return fmt.Sprintf("%s(hx)", g.pkg("loadingstates", "DataLoadingDelay")), nil
+	}
+	d, err := parseInterval(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s value: %w", name, err)
+	}
+	return fmt.Sprintf("%s(hx, %s)", g.pkg("loadingstates", "DataLoadingDelayBy"), g.duration(d)), nil
+}
+
+// parseInterval parses an htmx timing declaration. Like htmx, a bare number is in milliseconds.
+// It copies htmx/internal/interval, which packages outside htmx can't import.
+func parseInterval(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	ms, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timing %q", s)
+	}
+	return time.Duration(ms * float64(time.Millisecond)), nil
+}
+
+// quoteList quotes each item in a separated list, as Go arguments.
+func quoteList(s string, sep string) string {
+	var items []string
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, strconv.Quote(item))
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+// closestName finds the known attribute closest to an unknown one, to catch typos.
+func closestName(name string) string {
+	best, bestDistance := "", 3
+	for known := range rules {
+		if d := distance(name, known); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+// distance is the Levenshtein distance between two strings.
+func distance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,177 @@
+package lint
+
+import (
+	"bytes"
+	"strings"
+)
+
+// scanTempl finds the attributes in the element start tags of a templ file.
+// Go code outside of tags is skipped, along with spread attributes and the contents of script and style elements.
+func scanTempl(src []byte) []attribute {
+	var attrs []attribute
+	for i := 0; i < len(src); i++ {
+		if src[i] != '<' || i+1 >= len(src) || !isLetter(src[i+1]) {
+			continue
+		}
+
+		start := i + 1
+		i = start
+		for i < len(src) && isNameByte(src[i]) {
+			i++
+		}
+		tag := strings.ToLower(string(src[start:i]))
+
+		var tagAttrs []attribute
+		tagAttrs, i = scanTag(src, i)
+		attrs = append(attrs, tagAttrs...)
+
+		if tag == "script" || tag == "style" {
+			end := bytes.Index(bytes.ToLower(src[i:]), []byte("</"+tag))
+			if end == -1 {
+				break
+			}
+			i += end
+		}
+	}
+	return attrs
+}
+
+// scanTag reads the attributes of a start tag, and returns them with the index of the closing >.
+func scanTag(src []byte, i int) ([]attribute, int) {
+	var attrs []attribute
+	for i < len(src) {
+		switch c := src[i]; {
+		case isSpace(c), c == '/', c == '}':
+			i++
+			continue
+		case c == '>':
+			return attrs, i
+		case c == '{':
+			i = skipBraces(src, i)
+			continue
+		}
+
+		start := i
+		for i < len(src) && !isSpace(src[i]) && !strings.ContainsRune("=>/{}\"'", rune(src[i])) {
+			i++
+		}
+		name := string(src[start:i])
+		if name == "" {
+			// A stray quote or other invalid character. Skip it.
+			i++
+			continue
+		}
+
+		// Skip templ's conditional attributes, like `if cond {` and `else {`.
+		if name == "if" || name == "else" {
+			for i < len(src) && src[i] != '{' {
+				i = skipString(src, i)
+			}
+			i++
+			continue
+		}
+
+		a := attribute{
+			name:  strings.TrimSuffix(name, "?"),
+			value: Value{Text: "", Expr: false, Present: false},
+			start: start,
+			end:   i,
+			// Replacing a boolean attribute, like `hx-disable?={ cond }`, would drop its condition.
+			replaceable: !strings.HasSuffix(name, "?"),
+		}
+
+		j := skipSpaces(src, i)
+		if j < len(src) && src[j] == '=' {
+			j = skipSpaces(src, j+1)
+			a.value.Present = true
+			switch {
+			case j >= len(src):
+			case src[j] == '"' || src[j] == '\'':
+				end := bytes.IndexByte(src[j+1:], src[j])
+				if end == -1 {
+					end = len(src) - j - 1
+				}
+				a.value.Text = string(src[j+1 : j+1+end])
+				j += end + 2
+			case src[j] == '{':
+				end := skipBraces(src, j)
+				a.value.Text = strings.TrimSpace(string(src[j+1 : end-1]))
+				a.value.Expr = true
+				j = end
+			default:
+				end := j
+				for end < len(src) && !isSpace(src[end]) && src[end] != '>' {
+					end++
+				}
+				a.value.Text = string(src[j:end])
+				j = end
+			}
+			i = min(j, len(src))
+			a.end = i
+		}
+
+		if _, ok := Normalize(a.name); ok {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs, i
+}
+
+// skipBraces returns the index after the brace that closes the one at i, skipping Go strings.
+func skipBraces(src []byte, i int) int {
+	depth := 0
+	for i < len(src) {
+		switch src[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case '"', '\'', '`':
+			i = skipString(src, i)
+			continue
+		}
+		i++
+	}
+	return i
+}
+
+// skipString returns the index after the Go string or rune literal at i, or the next index if there isn't one.
+func skipString(src []byte, i int) int {
+	quote := src[i]
+	if quote != '"' && quote != '\'' && quote != '`' {
+		return i + 1
+	}
+	for i++; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return i
+}
+
+func skipSpaces(src []byte, i int) int {
+	for i < len(src) && isSpace(src[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isNameByte(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9' || c == '-' || c == ':' || c == '.' || c == '_'
+}